@@ -0,0 +1,176 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+= Akshara
+Brahmic scripts, like Devanagari, Bengali, Tamil and Thai, are written
+in syllables (aksharas): a consonant, or a cluster of consonants joined
+by a virama, followed by the signs of its vowel. Reversed rune by rune,
+the vowel signs end up before the consonants they belong to:
+
+	বিকট => [বি] [ক] [ট]
+
+The akshara unit compares the phrase syllable by syllable. An akshara
+is made of:
+
+	- a consonant, along with the consonants joined to it by a virama,
+	  except in Tamil, where the virama (pulli) doesn't join them
+	- or an independent vowel
+	- followed by its vowel signs and other marks
+	- in Thai, preceded by its leading vowel (เ แ โ ใ ไ) and followed by
+	  its vowel letters (ะ า ำ)
+
+Runes of other scripts are grouped in grapheme clusters. The Brahmic
+vowel signs are kept in the akshara unit, even though the nonspacing
+ones are removed with the other marks in every other unit.
+
+With the skeleton option, only the consonants are compared: vowels,
+vowel signs, viramas and every other mark of the Brahmic scripts are
+removed, so দামাদ reads as দমদ.
+
+The scripts from Devanagari to Malayalam share the same layout in their
+Unicode blocks, inherited from ISCII [1], which is how the classes of
+their runes are found.
+
+= References
+[1] https://en.wikipedia.org/wiki/Indian_Script_Code_for_Information_Interchange
+*/
+
+package main
+
+import (
+	"unicode"
+)
+
+const (
+	indicFirst     = 0x0900
+	indicLast      = 0x0D7F
+	indicBlockSize = 0x80
+	tamilBlock     = 0x0B80
+	thaiFirst      = 0x0E00
+	thaiLast       = 0x0E7F
+)
+
+// Removes the vowels and marks of the Brahmic scripts, leaving digits
+// and punctuation in place
+var ConsonantSkeleton Normalizer = RemoveFunc(func(r rune) bool {
+	return isBrahmic(r) && !isBrahmicConsonant(r) && (unicode.IsLetter(r) || unicode.IsMark(r))
+})
+
+/*
+Returns the number of runes of the akshara runes starts with.
+*/
+func aksharaLength(runes []rune) int {
+	if len(runes) == 0 {
+		return 0
+	}
+
+	i := 0
+	if isThaiLeadingVowel(runes[0]) {
+		i++
+		if i == len(runes) || !isBrahmicConsonant(runes[i]) {
+			return i
+		}
+	}
+
+	switch r := runes[i]; {
+	case isBrahmicConsonant(r):
+		i++
+		// Consonants joined by a virama
+		for i+1 < len(runes) && isVirama(runes[i]) && joinsConsonants(runes[i]) && isBrahmicConsonant(runes[i+1]) {
+			i += 2
+		}
+	case isBrahmic(r) && unicode.IsLetter(r):
+		i++
+	default:
+		return graphemeLength(runes)
+	}
+
+	for i < len(runes) && (isBrahmicSign(runes[i]) || isThaiFollowingVowel(runes[i])) {
+		i++
+	}
+
+	return i
+}
+
+/*
+Splits normalized spans in one unit per akshara.
+*/
+func aksharaUnits(spans []span) []span {
+	return clusterUnits(spans, aksharaLength)
+}
+
+func isBrahmic(r rune) bool {
+	return (r >= indicFirst && r <= indicLast) || (r >= thaiFirst && r <= thaiLast)
+}
+
+/*
+Reports whether r is a consonant letter of a Brahmic script.
+*/
+func isBrahmicConsonant(r rune) bool {
+	switch {
+	case r >= thaiFirst && r <= thaiLast:
+		return r >= 'ก' && r <= 'ฮ'
+	case r >= indicFirst && r <= indicLast:
+		return unicode.IsLetter(r) && !isIndependentVowel(r) && indicOffset(r) != 0x3D // avagraha
+	}
+
+	return false
+}
+
+/*
+Reports whether r is an independent vowel of the scripts from
+Devanagari to Malayalam.
+*/
+func isIndependentVowel(r rune) bool {
+	offset := indicOffset(r)
+	switch {
+	case offset >= 0x04 && offset <= 0x14, offset == 0x60, offset == 0x61:
+		return true
+	case r >= 'ॲ' && r <= 'ॷ':
+		return true
+	}
+
+	return false
+}
+
+/*
+Reports whether r is a vowel sign or another mark of a Brahmic script.
+*/
+func isBrahmicSign(r rune) bool {
+	return isBrahmic(r) && unicode.In(r, unicode.Mn, unicode.Mc)
+}
+
+func isVirama(r rune) bool {
+	return r >= indicFirst && r <= indicLast && indicOffset(r) == 0x4D
+}
+
+func joinsConsonants(virama rune) bool {
+	return virama-indicOffset(virama) != tamilBlock
+}
+
+func isThaiLeadingVowel(r rune) bool {
+	return r >= 'เ' && r <= 'ไ'
+}
+
+func isThaiFollowingVowel(r rune) bool {
+	return r == 'ะ' || r == 'า' || r == 'ำ' || r == 'ๅ'
+}
+
+/*
+Returns the position of r in its Unicode block.
+*/
+func indicOffset(r rune) rune {
+	return (r - indicFirst) % indicBlockSize
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestValidateAssertsAksharaPalindromes(t *testing.T) {
+	phrases := []string{
+		"রমাকান্তকামার",
+		"விகடகவி",
+		"माला मा",
+		"เกเขเก",
+	}
+
+	var palindrome Palindrome
+	for _, phrase := range phrases {
+		palindrome = Palindrome{Phrase: phrase, Unit: UnitAkshara}
+		palindrome.Validate()
+		Expect(t, palindrome.Valid, true)
+
+		palindrome = Palindrome{Phrase: phrase}
+		palindrome.Validate()
+		Expect(t, palindrome.Valid, false)
+	}
+}
+
+func TestValidateToCompareConsonantSkeletons(t *testing.T) {
+	palindrome := Palindrome{Phrase: "दामाद", Unit: UnitAkshara}
+	palindrome.Validate()
+	Expect(t, palindrome.Valid, false)
+
+	palindrome = Palindrome{Phrase: "दामाद", Unit: UnitAkshara, Skeleton: true}
+	palindrome.Validate()
+	Expect(t, palindrome.Valid, true)
+}
+
+func TestAksharaLengthToGroupSyllables(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected int
+	}{
+		{"", 0},
+		// Consonant and vowel sign
+		{"कि", 2},
+		// Conjunct, with a virama joining the consonants
+		{"क्षा", 4},
+		// Dead consonant at the end of the word
+		{"क्", 2},
+		// Tamil pulli doesn't join consonants
+		{"க்க", 2},
+		// Independent vowel and anusvara
+		{"अंक", 2},
+		// Thai leading and following vowels
+		{"เก่า", 4},
+		{"กา", 2},
+		// Other scripts fall back to grapheme clusters
+		{"🇯🇵x", 2},
+		{"ab", 1},
+	}
+
+	for _, test := range tests {
+		Expect(t, aksharaLength([]rune(test.text)), test.expected)
+	}
+}
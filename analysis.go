@@ -0,0 +1,209 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+= Distance to palindrome
+A phrase that isn't a palindrome can be turned into one by editing it.
+The analysis tells how many edits are needed, in two different ways:
+
+	insertions     units inserted anywhere in the phrase
+	substitutions  units replaced by the one in the mirrored position
+
+The minimum number of insertions is the number of units left out of the
+longest palindromic subsequence [1], found by dynamic programming in
+quadratic time and memory. That's why phrases longer than
+MaxAnalysisUnits are only analyzed for substitutions, which just need
+the mismatching pairs to be counted. Palindromes longer than
+MaxAnalysisBytes aren't analyzed at all, as even the substitutions of a
+phrase of a few megabytes take longer than a request should.
+
+Each distance comes with one of the repaired phrases it leads to, in
+normalized form, and the list of edits with their position in the
+original phrase. Insertions happen before the unit at the position,
+or at the end of the phrase when the position is its length.
+
+= References
+[1] https://en.wikipedia.org/wiki/Longest_palindromic_subsequence
+*/
+
+package main
+
+import (
+	"errors"
+)
+
+const (
+	// Phrases with more units than that aren't analyzed for insertions
+	MaxAnalysisUnits = 2000
+	// Palindromes longer than that aren't analyzed. See models.go
+	MaxAnalysisBytes = 64 << 10
+)
+
+const (
+	EditInsert     = "insert"
+	EditSubstitute = "substitute"
+)
+
+type Edit struct {
+	Operation string `json:"operation"`
+	Position  Offset `json:"position"`
+	Text      string `json:"text"`
+	// Unit replaced by a substitution
+	Replaces string `json:"replaces,omitempty"`
+}
+
+type Repair struct {
+	Distance int    `json:"distance"`
+	Repaired string `json:"repaired"`
+	Edits    []Edit `json:"edits"`
+}
+
+type Analysis struct {
+	// Missing when the phrase is longer than MaxAnalysisUnits
+	Insertions    *Repair `json:"insertions,omitempty"`
+	Substitutions *Repair `json:"substitutions"`
+}
+
+/*
+Analyzes how far the phrase is from being a palindrome.
+*/
+func (e *Engine) Analyze(phrase string) (Analysis, error) {
+	var analysis Analysis
+	if len(phrase) == 0 {
+		return analysis, errors.New("Invalid length")
+	}
+
+	units, err := e.units(phrase)
+	if err != nil {
+		return analysis, err
+	}
+
+	analysis.Substitutions = e.substitutions(phrase, units)
+	if len(units) <= MaxAnalysisUnits {
+		analysis.Insertions = e.insertions(phrase, units)
+	}
+
+	return analysis, nil
+}
+
+/*
+Replaces the second unit of every mismatching pair by the first one.
+*/
+func (e *Engine) substitutions(phrase string, units []span) *Repair {
+	repaired := make([]span, len(units))
+	copy(repaired, units)
+	starts, _ := unitOffsets(phrase, units)
+
+	repair := &Repair{Edits: []Edit{}}
+	for i, j := 0, len(units)-1; i < j; i, j = i+1, j-1 {
+		if units[i].text == units[j].text {
+			continue
+		}
+
+		repaired[j].text = units[i].text
+		repair.Distance++
+		repair.Edits = append(repair.Edits, Edit{
+			Operation: EditSubstitute,
+			Position:  starts[j],
+			Text:      units[i].text,
+			Replaces:  units[j].text,
+		})
+	}
+	repair.Repaired = e.Unit.join(repaired)
+
+	// Edits are listed in the order of their position
+	for i, j := 0, len(repair.Edits)-1; i < j; i, j = i+1, j-1 {
+		repair.Edits[i], repair.Edits[j] = repair.Edits[j], repair.Edits[i]
+	}
+
+	return repair
+}
+
+/*
+Mirrors every unit out of the longest palindromic subsequence.
+*/
+func (e *Engine) insertions(phrase string, units []span) *Repair {
+	n := len(units)
+	repair := &Repair{Edits: []Edit{}}
+	if n == 0 {
+		return repair
+	}
+	starts, ends := unitOffsets(phrase, units)
+
+	// cost[i*n+j] is the number of insertions units[i:j+1] needs
+	cost := make([]uint16, n*n)
+	for i := n - 2; i >= 0; i-- {
+		for j := i + 1; j < n; j++ {
+			switch {
+			case units[i].text == units[j].text:
+				cost[i*n+j] = cost[(i+1)*n+j-1]
+			case cost[(i+1)*n+j] <= cost[i*n+j-1]:
+				cost[i*n+j] = cost[(i+1)*n+j] + 1
+			default:
+				cost[i*n+j] = cost[i*n+j-1] + 1
+			}
+		}
+	}
+	repair.Distance = int(cost[n-1])
+
+	// Walk the table from the outside in, building both halves of the
+	// repaired phrase
+	var left, right []span
+	var before, after []Edit
+	i, j := 0, n-1
+	for i <= j {
+		switch {
+		case i == j:
+			left = append(left, units[i])
+			i++
+		case units[i].text == units[j].text:
+			left = append(left, units[i])
+			right = append(right, units[j])
+			i, j = i+1, j-1
+		case cost[(i+1)*n+j] <= cost[i*n+j-1]:
+			// Mirror units[i] after units[j]
+			left = append(left, units[i])
+			right = append(right, units[i])
+			after = append(after, Edit{
+				Operation: EditInsert,
+				Position:  ends[j],
+				Text:      units[i].text,
+			})
+			i++
+		default:
+			// Mirror units[j] before units[i]
+			left = append(left, units[j])
+			right = append(right, units[j])
+			before = append(before, Edit{
+				Operation: EditInsert,
+				Position:  starts[i],
+				Text:      units[j].text,
+			})
+			j--
+		}
+	}
+
+	for k := len(right) - 1; k >= 0; k-- {
+		left = append(left, right[k])
+	}
+	repair.Repaired = e.Unit.join(left)
+
+	// Edits are listed in the order of their position
+	repair.Edits = append(repair.Edits, before...)
+	for k := len(after) - 1; k >= 0; k-- {
+		repair.Edits = append(repair.Edits, after[k])
+	}
+
+	return repair
+}
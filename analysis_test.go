@@ -0,0 +1,77 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeToReturnMinimumInsertions(t *testing.T) {
+	analysis, err := new(Engine).Analyze("Race car!")
+
+	Expect(t, err, nil)
+	Expect(t, analysis.Insertions.Distance, 0)
+
+	analysis, _ = new(Engine).Analyze("abcb")
+	Expect(t, analysis.Insertions.Distance, 1)
+	Expect(t, analysis.Insertions.Repaired, "abcba")
+	Expect(t, len(analysis.Insertions.Edits), 1)
+	Expect(t, analysis.Insertions.Edits[0], Edit{Operation: EditInsert, Position: Offset{4, 4}, Text: "a"})
+}
+
+func TestAnalyzeToReturnMinimumSubstitutions(t *testing.T) {
+	analysis, _ := new(Engine).Analyze("Rats live on no evil stab")
+
+	Expect(t, analysis.Substitutions.Distance, 1)
+	Expect(t, analysis.Substitutions.Repaired, "ratsliveonnoevilstar")
+	Expect(t, analysis.Substitutions.Edits[0], Edit{
+		Operation: EditSubstitute,
+		Position:  Offset{24, 24},
+		Text:      "r",
+		Replaces:  "b",
+	})
+}
+
+func TestAnalyzeToMapEditsToOriginalPhrase(t *testing.T) {
+	analysis, _ := new(Engine).Analyze("Ôba, ab")
+
+	// Normalized as "obaab", an o is missing at the end
+	Expect(t, analysis.Insertions.Distance, 1)
+	Expect(t, analysis.Insertions.Repaired, "obaabo")
+	Expect(t, analysis.Insertions.Edits[0].Position, Offset{8, 7})
+}
+
+func TestAnalyzeToRepairWordPalindromes(t *testing.T) {
+	engine := &Engine{Unit: UnitWord}
+
+	analysis, _ := engine.Analyze("Fall leaves after leaves")
+	Expect(t, analysis.Insertions.Distance, 1)
+	Expect(t, analysis.Insertions.Repaired, "fall leaves after leaves fall")
+	Expect(t, analysis.Substitutions.Distance, 2)
+}
+
+func TestAnalyzeToSkipInsertionsOnLongPhrases(t *testing.T) {
+	phrase := make([]byte, MaxAnalysisUnits+1)
+	for i := range phrase {
+		phrase[i] = 'a' + byte(i%26)
+	}
+
+	analysis, _ := new(Engine).Analyze(string(phrase))
+	Expect(t, analysis.Insertions == nil, true)
+	ExpectNotNil(t, analysis.Substitutions)
+}
+
+func TestPalindromeAnalyzeToKeepAnalysis(t *testing.T) {
+	palindrome := Palindrome{Phrase: "racecars"}
+	palindrome.Validate()
+	palindrome.Analyze()
+
+	Expect(t, palindrome.Valid, false)
+	Expect(t, palindrome.Analysis.Insertions.Distance, 1)
+}
+
+func TestPalindromeAnalyzeToSkipLongPhrases(t *testing.T) {
+	palindrome := Palindrome{Phrase: strings.Repeat("ab", MaxAnalysisBytes)}
+
+	Expect(t, palindrome.Analyze(), nil)
+	Expect(t, palindrome.Analysis == nil, true)
+}
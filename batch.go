@@ -0,0 +1,291 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+= Batch submission
+Importers add thousands of phrases at once. Instead of a request per
+phrase, a batch carries all of them, either as a JSON array or as
+NDJSON, one JSON object per line.
+
+Each item is decoded and validated on its own, concurrently, so an item
+that can't be decoded or validated is reported as invalid without
+failing the others. NDJSON lines are independent of each other, but a
+JSON array has to be well formed as a whole: only its items may be of
+the wrong type.
+
+The valid items are then inserted by a single unordered bulk write
+(see dao.go), which keeps inserting past the items that fail. Items
+rejected by the unique indexes are reported as duplicates, along with
+the ID of the palindrome stored with the same phrase, or with the same
+skeleton for items validated with confusables, which may be another
+item of the batch. Items validated with confusables that look like a
+stored palindrome, or like an item before them, are reported as
+duplicates of it before the bulk write (see confusables.go).
+*/
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+
+	// Third party packages
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// Most items accepted in a batch
+	MaxBatchItems = 10000
+	// Largest batch accepted, in bytes
+	MaxBatchSize = 32 << 20
+)
+
+const (
+	BatchCreated   = "created"
+	BatchDuplicate = "duplicate"
+	BatchInvalid   = "invalid"
+	BatchError     = "error"
+)
+
+var errTooManyItems = errors.New("Too many items")
+
+// Outcome of an item of the batch, in the same position
+type BatchItem struct {
+	Status string `json:"status"`
+	// Of the created palindrome, or of the stored one for duplicates
+	ID     bson.ObjectId `json:"id,omitempty"`
+	Phrase string        `json:"phrase,omitempty"`
+	Valid  bool          `json:"valid"`
+	Error  string        `json:"error,omitempty"`
+}
+
+/*
+Reads the items of a batch, either a JSON array or, with ndjson, one
+JSON value per line. Blank lines are skipped.
+*/
+func decodeBatch(r io.Reader, ndjson bool) ([]json.RawMessage, error) {
+	var items []json.RawMessage
+	if ndjson {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64<<10), MaxBatchSize)
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			if len(items) == MaxBatchItems {
+				return nil, errTooManyItems
+			}
+			items = append(items, append(json.RawMessage(nil), line...))
+		}
+		return items, scanner.Err()
+	}
+
+	decoder := json.NewDecoder(r)
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	if token != json.Delim('[') {
+		return nil, errors.New("Invalid batch")
+	}
+
+	for decoder.More() {
+		var item json.RawMessage
+		if err := decoder.Decode(&item); err != nil {
+			return nil, err
+		}
+		if len(items) == MaxBatchItems {
+			return nil, errTooManyItems
+		}
+		items = append(items, item)
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+/*
+Decodes and validates every item concurrently, with the given number of
+workers. Items that fail are marked invalid in the results; the others
+are left with no status yet.
+*/
+func validateBatch(ctx context.Context, items []json.RawMessage, workers int) ([]Palindrome, []BatchItem, error) {
+	palindromes := make([]Palindrome, len(items))
+	results := make([]BatchItem, len(items))
+
+	_, err := runParallel(ctx, workers, len(items), func(k int) bool {
+		if err := json.Unmarshal(items[k], &palindromes[k]); err != nil {
+			results[k] = BatchItem{Status: BatchInvalid, Error: "Invalid request"}
+			return true
+		}
+
+		if len(palindromes[k].Phrase) > MaxStoredPhraseSize {
+			results[k] = BatchItem{Status: BatchInvalid, Error: "Phrase too large"}
+			return true
+		}
+
+		results[k].Phrase = palindromes[k].Phrase
+		if err := palindromes[k].Validate(); err != nil {
+			results[k].Status, results[k].Error = BatchInvalid, "Invalid palindrome"
+			return true
+		}
+
+		results[k].Valid = palindromes[k].Valid
+		palindromes[k].SetSkeleton()
+		return true
+	})
+
+	return palindromes, results, err
+}
+
+/*
+Inserts the validated items of the batch, completing their results.
+*/
+func insertBatch(dao *Dao, palindromes []Palindrome, results []BatchItem) {
+	for k := range palindromes {
+		if results[k].Status == "" {
+			palindromes[k].ID = bson.NewObjectId()
+		}
+	}
+
+	if err := markLookalikes(dao, palindromes, results); err != nil {
+		log.Println("[palindromes] Lookalikes lookup: ", err)
+		for k := range results {
+			if results[k].Status == "" {
+				results[k].Status, results[k].Error = BatchError, "Database error"
+			}
+		}
+		return
+	}
+
+	var docs []Palindrome
+	var positions []int
+	for k := range palindromes {
+		if results[k].Status != "" {
+			continue
+		}
+
+		docs = append(docs, palindromes[k])
+		positions = append(positions, k)
+	}
+	if len(docs) == 0 {
+		return
+	}
+
+	failures, err := dao.InsertAll(docs)
+	if err != nil {
+		log.Println("[palindromes] Failed batch insert: ", err)
+		for _, k := range positions {
+			results[k].Status, results[k].Error = BatchError, "Database error"
+		}
+		return
+	}
+
+	var phrases, keys []string
+	for i, k := range positions {
+		failure, failed := failures[i]
+		switch {
+		case !failed:
+			results[k].Status, results[k].ID = BatchCreated, docs[i].ID
+		case mgo.IsDup(failure):
+			results[k].Status = BatchDuplicate
+			phrases = append(phrases, docs[i].Phrase)
+			if docs[i].Key != "" {
+				keys = append(keys, docs[i].Key)
+			}
+		default:
+			results[k].Status, results[k].Error = BatchError, "Database error"
+			log.Println("[palindromes] Failed insert: ", failure)
+		}
+	}
+	if len(phrases) == 0 {
+		return
+	}
+
+	ids, err := dao.FindIDsByPhrase(phrases)
+	if err != nil {
+		log.Println("[palindromes] Duplicates lookup: ", err)
+		return
+	}
+	keyIDs := make(map[string]bson.ObjectId)
+	if len(keys) > 0 {
+		keyIDs, err = dao.FindIDsByKey(keys)
+		if err != nil {
+			log.Println("[palindromes] Duplicates lookup: ", err)
+			return
+		}
+	}
+	for i, k := range positions {
+		if results[k].Status != BatchDuplicate {
+			continue
+		}
+
+		// The phrase may be stored, or a phrase looking like it
+		id, found := ids[docs[i].Phrase]
+		if !found {
+			id = keyIDs[docs[i].Key]
+		}
+		results[k].ID = id
+	}
+}
+
+/*
+Marks the items validated with confusables that look like a palindrome
+stored without them, or like an item before them, as duplicates of it.
+The ones looking like a palindrome stored with them are left to the
+unique index.
+*/
+func markLookalikes(dao *Dao, palindromes []Palindrome, results []BatchItem) error {
+	var lookalikes []string
+	for k := range palindromes {
+		if results[k].Status == "" && palindromes[k].Confusables {
+			lookalikes = append(lookalikes, palindromes[k].skeleton())
+		}
+	}
+	if len(lookalikes) == 0 {
+		return nil
+	}
+
+	ids, err := dao.FindIDsByLookalike(lookalikes)
+	if err != nil {
+		return err
+	}
+
+	for k := range palindromes {
+		if results[k].Status != "" {
+			continue
+		}
+
+		skeleton := palindromes[k].skeleton()
+		id, found := ids[skeleton]
+		switch {
+		case found && palindromes[k].Confusables:
+			results[k].Status, results[k].ID = BatchDuplicate, id
+		case !found:
+			ids[skeleton] = palindromes[k].ID
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestDecodeBatchToReadJSONArrays(t *testing.T) {
+	items, err := decodeBatch(strings.NewReader(`[{"phrase": "racecar"}, 42, {"phrase": ""}]`), false)
+
+	Expect(t, err, nil)
+	Expect(t, len(items), 3)
+	Expect(t, string(items[1]), "42")
+}
+
+func TestDecodeBatchToReadNDJSON(t *testing.T) {
+	body := "{\"phrase\": \"racecar\"}\n\n{\"phrase\": \nnot json\n"
+
+	items, err := decodeBatch(strings.NewReader(body), true)
+	Expect(t, err, nil)
+	Expect(t, len(items), 3)
+	Expect(t, string(items[2]), "not json")
+}
+
+func TestDecodeBatchToFailOnMalformedArray(t *testing.T) {
+	bodies := []string{
+		`{"phrase": "racecar"}`,
+		`[{"phrase": "racecar"}, {"phrase"`,
+	}
+
+	for _, body := range bodies {
+		_, err := decodeBatch(strings.NewReader(body), false)
+		ExpectNotNil(t, err)
+	}
+}
+
+func TestDecodeBatchToFailOnTooManyItems(t *testing.T) {
+	body := strings.Repeat("{\"phrase\": \"racecar\"}\n", MaxBatchItems+1)
+
+	_, err := decodeBatch(strings.NewReader(body), true)
+	Expect(t, err, errTooManyItems)
+}
+
+func TestValidateBatchToValidateEachItem(t *testing.T) {
+	items, _ := decodeBatch(strings.NewReader("{\"phrase\": \"Racecar\"}\nnot json\n{\"phrase\": \"\"}\n{\"phrase\": \"racecars\"}\n"), true)
+
+	palindromes, results, err := validateBatch(context.Background(), items, 1)
+	Expect(t, err, nil)
+	Expect(t, len(palindromes), 4)
+
+	Expect(t, results[0].Status, "")
+	Expect(t, results[0].Valid, true)
+	Expect(t, palindromes[0].Lookalike, "Racecar")
+	Expect(t, results[1].Status, BatchInvalid)
+	Expect(t, results[1].Error, "Invalid request")
+	Expect(t, results[2].Status, BatchInvalid)
+	Expect(t, results[2].Error, "Invalid palindrome")
+	Expect(t, results[3].Status, "")
+	Expect(t, results[3].Valid, false)
+}
+
+func TestValidateBatchToRejectPhrasesTooLargeToStore(t *testing.T) {
+	phrase := strings.Repeat("a", MaxStoredPhraseSize+1)
+	items, _ := decodeBatch(strings.NewReader("{\"phrase\": \""+phrase+"\"}\n"), true)
+
+	_, results, _ := validateBatch(context.Background(), items, 1)
+	Expect(t, results[0].Status, BatchInvalid)
+	Expect(t, results[0].Error, "Phrase too large")
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+= Chinese
+Chinese is written both in traditional and in simplified characters,
+and a phrase often mixes them: 來 and 来 are the same character. The
+variant folding replaces the traditional characters by their simplified
+form (see chinese_dict.go) before they are compared:
+
+	上海自來水来自海上 => 上海自来水来自海上
+
+Phonetic palindromes read the same backward syllable by syllable, even
+when the characters differ (事实是, shi shi shi). The mode selects how
+Chinese phrases are compared:
+
+	variant-folded  each character, traditional or simplified (the default)
+	strict          each character, as it's written
+	pinyin          each character by its pinyin syllable, without tones
+
+In the pinyin mode, the character unit compares each Han character by
+its whole syllable, instead of letter by letter.
+*/
+
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+type ChineseMode string
+
+const (
+	ChineseVariantFolded ChineseMode = "variant-folded"
+	ChineseStrict        ChineseMode = "strict"
+	ChinesePinyin        ChineseMode = "pinyin"
+)
+
+var (
+	VariantFolding Normalizer = NormalizerFunc(func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if simplified, ok := chineseVariants[r]; ok {
+				return simplified
+			}
+			return r
+		}, s)
+	})
+
+	PinyinReading = Transliteration(chinesePinyin)
+)
+
+/*
+Returns the stages implementing the mode.
+*/
+func (mode ChineseMode) normalizer() (Pipeline, bool) {
+	switch mode {
+	case "", ChineseVariantFolded:
+		return Pipeline{VariantFolding}, true
+	case ChineseStrict:
+		return nil, true
+	case ChinesePinyin:
+		return Pipeline{VariantFolding, PinyinReading}, true
+	}
+
+	return nil, false
+}
+
+/*
+Splits normalized spans in one unit per rune, except for the spans of
+Han characters, which are a unit each.
+*/
+func syllableUnits(phrase string, spans []span) []span {
+	var units []span
+	for _, s := range spans {
+		if isHan(phrase[s.start:s.end]) {
+			units = append(units, s)
+			continue
+		}
+		units = append(units, characterUnits([]span{s})...)
+	}
+
+	return units
+}
+
+func isHan(s string) bool {
+	for _, r := range s {
+		if !unicode.Is(unicode.Han, r) {
+			return false
+		}
+	}
+
+	return s != ""
+}
@@ -0,0 +1,310 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Embedded Chinese variant and pinyin tables.
+
+chineseVariants maps traditional characters to their simplified form.
+chinesePinyin maps simplified characters to their most common reading
+in pinyin, without tones. Like the Japanese dictionary, the tables are
+not complete, but they cover the most common characters and the ones
+used in the well known palindromes.
+*/
+
+package main
+
+var chineseVariants = map[rune]rune{
+	'亂': '乱', '亞': '亚', '來': '来', '個': '个', '們': '们', '傳': '传', '傷': '伤', '價': '价',
+	'億': '亿', '優': '优', '兒': '儿', '內': '内', '兩': '两', '冊': '册', '劃': '划', '劍': '剑',
+	'動': '动', '務': '务', '勝': '胜', '勞': '劳', '區': '区', '協': '协', '參': '参', '叢': '丛',
+	'吳': '吴', '員': '员', '問': '问', '啟': '启', '單': '单', '嗎': '吗', '嚴': '严', '國': '国',
+	'圍': '围', '園': '园', '圓': '圆', '圖': '图', '團': '团', '場': '场', '壓': '压', '壞': '坏',
+	'壯': '壮', '壽': '寿', '夠': '够', '夢': '梦', '奪': '夺', '奮': '奋', '婦': '妇', '媽': '妈',
+	'孫': '孙', '學': '学', '實': '实', '寧': '宁', '審': '审', '寫': '写', '寶': '宝', '將': '将',
+	'專': '专', '尋': '寻', '對': '对', '導': '导', '屬': '属', '島': '岛', '嶺': '岭', '師': '师',
+	'帶': '带', '幫': '帮', '幹': '干', '廣': '广', '廳': '厅', '張': '张', '強': '强', '彈': '弹',
+	'彎': '弯', '後': '后', '徑': '径', '從': '从', '復': '复', '愛': '爱', '態': '态', '憂': '忧',
+	'憶': '忆', '應': '应', '懷': '怀', '戀': '恋', '戰': '战', '戲': '戏', '戶': '户', '掃': '扫',
+	'掛': '挂', '揚': '扬', '換': '换', '擇': '择', '擊': '击', '擔': '担', '據': '据', '擠': '挤',
+	'敗': '败', '數': '数', '斷': '断', '於': '于', '時': '时', '晝': '昼', '暫': '暂', '曆': '历',
+	'書': '书', '會': '会', '東': '东', '條': '条', '楊': '杨', '業': '业', '極': '极', '構': '构',
+	'槍': '枪', '樂': '乐', '樓': '楼', '標': '标', '樣': '样', '樹': '树', '橋': '桥', '機': '机',
+	'橫': '横', '檢': '检', '權': '权', '歡': '欢', '歲': '岁', '歷': '历', '歸': '归', '殘': '残',
+	'殺': '杀', '氣': '气', '決': '决', '沒': '没', '況': '况', '淚': '泪', '測': '测', '湯': '汤',
+	'溫': '温', '滿': '满', '漁': '渔', '漢': '汉', '濕': '湿', '濟': '济', '灣': '湾', '為': '为',
+	'無': '无', '煙': '烟', '熱': '热', '燈': '灯', '營': '营', '爺': '爷', '牆': '墙', '狀': '状',
+	'獅': '狮', '獎': '奖', '獨': '独', '現': '现', '環': '环', '產': '产', '畫': '画', '當': '当',
+	'發': '发', '盡': '尽', '監': '监', '確': '确', '碼': '码', '禮': '礼', '種': '种', '窮': '穷',
+	'競': '竞', '筆': '笔', '節': '节', '範': '范', '簡': '简', '紀': '纪', '約': '约', '紅': '红',
+	'紙': '纸', '級': '级', '細': '细', '組': '组', '結': '结', '絕': '绝', '給': '给', '統': '统',
+	'經': '经', '綠': '绿', '維': '维', '網': '网', '緊': '紧', '線': '线', '練': '练', '總': '总',
+	'罵': '骂', '義': '义', '習': '习', '聖': '圣', '聯': '联', '聲': '声', '職': '职', '聽': '听',
+	'腦': '脑', '與': '与', '興': '兴', '舊': '旧', '莊': '庄', '華': '华', '萬': '万', '葉': '叶',
+	'藝': '艺', '藥': '药', '蘭': '兰', '號': '号', '蟲': '虫', '衛': '卫', '裡': '里', '見': '见',
+	'親': '亲', '覺': '觉', '覽': '览', '觀': '观', '記': '记', '設': '设', '許': '许', '詞': '词',
+	'詩': '诗', '話': '话', '認': '认', '語': '语', '說': '说', '誰': '谁', '請': '请', '論': '论',
+	'講': '讲', '謝': '谢', '證': '证', '識': '识', '議': '议', '護': '护', '讀': '读', '變': '变',
+	'讓': '让', '貓': '猫', '貝': '贝', '負': '负', '財': '财', '貨': '货', '貴': '贵', '買': '买',
+	'費': '费', '資': '资', '賣': '卖', '賽': '赛', '贏': '赢', '趕': '赶', '跡': '迹', '車': '车',
+	'輕': '轻', '輸': '输', '辦': '办', '農': '农', '這': '这', '週': '周', '進': '进', '運': '运',
+	'過': '过', '達': '达', '遠': '远', '適': '适', '遲': '迟', '選': '选', '還': '还', '邊': '边',
+	'郵': '邮', '鄉': '乡', '鄭': '郑', '鄰': '邻', '醜': '丑', '醫': '医', '釘': '钉', '針': '针',
+	'釣': '钓', '銀': '银', '錢': '钱', '錯': '错', '鎖': '锁', '鐘': '钟', '鐵': '铁', '長': '长',
+	'門': '门', '開': '开', '閒': '闲', '間': '间', '關': '关', '陰': '阴', '陸': '陆', '陽': '阳',
+	'隊': '队', '際': '际', '隻': '只', '雖': '虽', '雙': '双', '雜': '杂', '雞': '鸡', '離': '离',
+	'難': '难', '雲': '云', '電': '电', '霧': '雾', '靈': '灵', '靜': '静', '韓': '韩', '韻': '韵',
+	'響': '响', '頁': '页', '順': '顺', '須': '须', '預': '预', '頓': '顿', '領': '领', '頭': '头',
+	'頻': '频', '題': '题', '顏': '颜', '願': '愿', '類': '类', '顧': '顾', '顯': '显', '風': '风',
+	'飛': '飞', '飯': '饭', '飲': '饮', '飽': '饱', '餘': '余', '館': '馆', '馬': '马', '馳': '驰',
+	'騎': '骑', '驅': '驱', '驗': '验', '驚': '惊', '體': '体', '髮': '发', '鬥': '斗', '鬧': '闹',
+	'鬱': '郁', '魚': '鱼', '魯': '鲁', '鮮': '鲜', '鳥': '鸟', '鳳': '凤', '鴨': '鸭', '鵝': '鹅',
+	'鹽': '盐', '麗': '丽', '麥': '麦', '麵': '面', '麼': '么', '黃': '黄', '點': '点', '黨': '党',
+	'齊': '齐', '齒': '齿', '齡': '龄', '龍': '龙', '龜': '龟',
+}
+
+var chinesePinyin = map[rune]string{
+	'爱': "ai",
+	'安': "an", '岸': "an",
+	'八': "ba", '吧': "ba", '把': "ba", '爸': "ba", '罢': "ba",
+	'白': "bai", '百': "bai", '败': "bai",
+	'办': "ban",
+	'帮': "bang",
+	'宝': "bao", '饱': "bao",
+	'贝': "bei",
+	'笔': "bi",
+	'变': "bian", '边': "bian",
+	'标': "biao",
+	'不': "bu",
+	'财': "cai",
+	'参': "can", '残': "can",
+	'册': "ce", '测': "ce",
+	'产': "chan",
+	'场': "chang", '常': "chang", '长': "chang",
+	'车': "che",
+	'吃': "chi", '迟': "chi", '驰': "chi", '齿': "chi",
+	'虫': "chong",
+	'丑': "chou",
+	'出': "chu",
+	'传': "chuan", '船': "chuan",
+	'春': "chun",
+	'词': "ci",
+	'丛': "cong", '从': "cong",
+	'错': "cuo",
+	'大': "da", '打': "da", '达': "da",
+	'带': "dai",
+	'单': "dan", '弹': "dan", '担': "dan",
+	'党': "dang", '当': "dang",
+	'导': "dao", '岛': "dao",
+	'得': "de", '的': "de",
+	'灯': "deng",
+	'地': "di", '底': "di", '弟': "di", '第': "di",
+	'店': "dian", '点': "dian", '电': "dian",
+	'钓': "diao",
+	'钉': "ding",
+	'东': "dong", '冬': "dong", '动': "dong",
+	'斗': "dou", '都': "dou",
+	'独': "du", '读': "du",
+	'断': "duan",
+	'对': "dui", '队': "dui",
+	'顿': "dun",
+	'多': "duo", '夺': "duo",
+	'鹅': "e",
+	'二': "er", '儿': "er",
+	'发': "fa", '法': "fa",
+	'范': "fan", '饭': "fan",
+	'费': "fei", '飞': "fei",
+	'奋': "fen",
+	'凤': "feng", '风': "feng",
+	'复': "fu", '妇': "fu", '父': "fu", '负': "fu",
+	'干': "gan", '赶': "gan",
+	'高': "gao",
+	'个': "ge", '哥': "ge", '歌': "ge",
+	'给': "gei",
+	'够': "gou", '构': "gou", '狗': "gou",
+	'顾': "gu",
+	'挂': "gua",
+	'关': "guan", '观': "guan", '馆': "guan",
+	'广': "guang",
+	'归': "gui", '贵': "gui", '龟': "gui",
+	'国': "guo", '果': "guo", '过': "guo",
+	'海': "hai", '还': "hai",
+	'汉': "han", '韩': "han",
+	'号': "hao", '好': "hao",
+	'合': "he", '和': "he", '喝': "he", '河': "he",
+	'横': "heng",
+	'红': "hong",
+	'后': "hou",
+	'户': "hu", '护': "hu",
+	'划': "hua", '化': "hua", '华': "hua", '画': "hua", '花': "hua", '话': "hua",
+	'坏': "huai", '怀': "huai",
+	'换': "huan", '欢': "huan", '环': "huan",
+	'黄': "huang",
+	'会': "hui", '回': "hui", '灰': "hui",
+	'火': "huo", '货': "huo",
+	'几': "ji", '击': "ji", '挤': "ji", '机': "ji", '极': "ji", '济': "ji", '级': "ji", '纪': "ji", '记': "ji", '迹': "ji", '际': "ji", '鸡': "ji",
+	'价': "jia", '加': "jia", '家': "jia",
+	'件': "jian", '剑': "jian", '检': "jian", '监': "jian", '简': "jian", '见': "jian", '间': "jian",
+	'奖': "jiang", '将': "jiang", '讲': "jiang",
+	'结': "jie", '节': "jie",
+	'今': "jin", '尽': "jin", '紧': "jin", '近': "jin", '进': "jin", '金': "jin",
+	'井': "jing", '京': "jing", '径': "jing", '惊': "jing", '竞': "jing", '精': "jing", '经': "jing", '静': "jing",
+	'久': "jiu", '九': "jiu", '就': "jiu", '旧': "jiu", '酒': "jiu",
+	'据': "ju",
+	'决': "jue", '绝': "jue", '觉': "jue",
+	'开': "kai",
+	'看': "kan",
+	'可': "ke", '客': "ke",
+	'口': "kou",
+	'况': "kuang",
+	'来': "lai",
+	'兰': "lan", '览': "lan",
+	'劳': "lao",
+	'乐': "le", '了': "le",
+	'泪': "lei", '类': "lei",
+	'丽': "li", '力': "li", '历': "li", '李': "li", '理': "li", '礼': "li", '离': "li", '立': "li", '里': "li",
+	'恋': "lian", '练': "lian", '联': "lian",
+	'两': "liang",
+	'岭': "ling", '灵': "ling", '邻': "ling", '领': "ling", '龄': "ling",
+	'六': "liu", '流': "liu", '留': "liu",
+	'龙': "long",
+	'楼': "lou",
+	'路': "lu", '陆': "lu", '鲁': "lu",
+	'乱': "luan",
+	'论': "lun",
+	'绿': "lü",
+	'吗': "ma", '妈': "ma", '码': "ma", '马': "ma", '骂': "ma",
+	'买': "mai", '卖': "mai", '麦': "mai",
+	'满': "man",
+	'毛': "mao", '猫': "mao",
+	'么': "me",
+	'妹': "mei", '每': "mei", '没': "mei", '美': "mei",
+	'们': "men", '门': "men",
+	'梦': "meng",
+	'面': "mian",
+	'明': "ming",
+	'木': "mu", '母': "mu",
+	'拿': "na", '那': "na",
+	'南': "nan", '男': "nan", '难': "nan",
+	'脑': "nao", '闹': "nao",
+	'内': "nei",
+	'能': "neng",
+	'你': "ni",
+	'年': "nian",
+	'鸟': "niao",
+	'宁': "ning",
+	'牛': "niu",
+	'农': "nong",
+	'女': "nü",
+	'频': "pin",
+	'七': "qi", '启': "qi", '期': "qi", '气': "qi", '起': "qi", '骑': "qi", '齐': "qi",
+	'前': "qian", '千': "qian", '钱': "qian",
+	'墙': "qiang", '强': "qiang", '枪': "qiang",
+	'桥': "qiao",
+	'亲': "qin",
+	'情': "qing", '清': "qing", '请': "qing", '轻': "qing", '青': "qing",
+	'穷': "qiong",
+	'秋': "qiu",
+	'区': "qu", '去': "qu", '驱': "qu",
+	'权': "quan",
+	'确': "que",
+	'让': "rang",
+	'热': "re",
+	'人': "ren", '认': "ren",
+	'日': "ri",
+	'入': "ru",
+	'赛': "sai",
+	'三': "san",
+	'扫': "sao",
+	'杀': "sha",
+	'山': "shan",
+	'上': "shang", '伤': "shang",
+	'少': "shao",
+	'设': "she",
+	'谁': "shei",
+	'审': "shen",
+	'圣': "sheng", '声': "sheng", '生': "sheng", '胜': "sheng",
+	'世': "shi", '事': "shi", '使': "shi", '十': "shi", '史': "shi", '实': "shi", '市': "shi", '师': "shi", '时': "shi", '是': "shi", '湿': "shi", '狮': "shi", '石': "shi", '识': "shi", '诗': "shi", '适': "shi", '食': "shi",
+	'寿': "shou", '手': "shou",
+	'书': "shu", '属': "shu", '数': "shu", '树': "shu", '输': "shu",
+	'双': "shuang",
+	'水': "shui",
+	'顺': "shun",
+	'说': "shuo",
+	'丝': "si", '司': "si", '四': "si", '寺': "si", '思': "si", '死': "si", '私': "si",
+	'岁': "sui", '虽': "sui",
+	'孙': "sun",
+	'所': "suo", '锁': "suo",
+	'他': "ta", '她': "ta", '它': "ta",
+	'态': "tai",
+	'汤': "tang",
+	'体': "ti", '题': "ti",
+	'天': "tian", '田': "tian",
+	'条': "tiao",
+	'铁': "tie",
+	'厅': "ting", '听': "ting",
+	'同': "tong", '统': "tong",
+	'头': "tou",
+	'图': "tu", '土': "tu",
+	'团': "tuan",
+	'万': "wan", '弯': "wan", '湾': "wan",
+	'望': "wang", '王': "wang", '网': "wang",
+	'为': "wei", '卫': "wei", '围': "wei", '维': "wei",
+	'文': "wen", '温': "wen", '问': "wen", '闻': "wen",
+	'我': "wo",
+	'五': "wu", '务': "wu", '午': "wu", '吴': "wu", '无': "wu", '物': "wu", '雾': "wu",
+	'习': "xi", '戏': "xi", '细': "xi",
+	'下': "xia", '夏': "xia",
+	'先': "xian", '显': "xian", '现': "xian", '线': "xian", '闲': "xian", '鲜': "xian",
+	'乡': "xiang", '向': "xiang", '响': "xiang", '想': "xiang", '香': "xiang",
+	'小': "xiao", '笑': "xiao",
+	'写': "xie", '协': "xie", '谢': "xie",
+	'心': "xin", '新': "xin",
+	'兴': "xing", '星': "xing", '行': "xing",
+	'许': "xu", '须': "xu",
+	'选': "xuan",
+	'学': "xue", '雪': "xue",
+	'寻': "xun",
+	'亚': "ya", '压': "ya", '鸭': "ya",
+	'严': "yan", '烟': "yan", '盐': "yan", '颜': "yan", '验': "yan",
+	'扬': "yang", '杨': "yang", '样': "yang", '洋': "yang", '羊': "yang", '阳': "yang",
+	'药': "yao", '要': "yao",
+	'业': "ye", '也': "ye", '叶': "ye", '夜': "ye", '爷': "ye", '页': "ye",
+	'一': "yi", '义': "yi", '亿': "yi", '以': "yi", '依': "yi", '医': "yi", '已': "yi", '忆': "yi", '意': "yi", '艺': "yi", '衣': "yi", '议': "yi",
+	'银': "yin", '阴': "yin", '饮': "yin",
+	'应': "ying", '影': "ying", '英': "ying", '营': "ying", '赢': "ying",
+	'优': "you", '又': "you", '友': "you", '右': "you", '忧': "you", '有': "you", '油': "you", '游': "you", '邮': "you",
+	'与': "yu", '于': "yu", '余': "yu", '渔': "yu", '玉': "yu", '语': "yu", '郁': "yu", '雨': "yu", '预': "yu", '鱼': "yu",
+	'员': "yuan", '园': "yuan", '圆': "yuan", '愿': "yuan", '远': "yuan",
+	'月': "yue", '约': "yue",
+	'云': "yun", '运': "yun", '韵': "yun",
+	'杂': "za",
+	'再': "zai", '在': "zai",
+	'暂': "zan",
+	'择': "ze",
+	'战': "zhan",
+	'张': "zhang",
+	'着': "zhe", '这': "zhe",
+	'针': "zhen",
+	'证': "zheng", '郑': "zheng",
+	'只': "zhi", '知': "zhi", '纸': "zhi", '职': "zhi",
+	'中': "zhong", '种': "zhong", '钟': "zhong",
+	'周': "zhou", '昼': "zhou",
+	'专': "zhuan",
+	'壮': "zhuang", '庄': "zhuang", '状': "zhuang",
+	'子': "zi", '字': "zi", '自': "zi", '资': "zi",
+	'总': "zong",
+	'走': "zou",
+	'组': "zu", '足': "zu",
+	'作': "zuo", '做': "zuo", '坐': "zuo", '左': "zuo",
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestValidateToCompareChineseByMode(t *testing.T) {
+	tests := []struct {
+		phrase   string
+		mode     ChineseMode
+		expected bool
+	}{
+		{"上海自来水来自海上", "", true},
+		{"上海自來水来自海上", "", true},
+		{"上海自來水来自海上", ChineseVariantFolded, true},
+		{"上海自來水来自海上", ChineseStrict, false},
+		{"人人為我，我为人人", "", true},
+		{"上海自來水来自海上", ChinesePinyin, true},
+		// Phonetic palindromes
+		{"事实是", "", false},
+		{"事实是", ChinesePinyin, true},
+		{"妈骂马", ChinesePinyin, true},
+		{"上海", ChinesePinyin, false},
+	}
+
+	var palindrome Palindrome
+	for _, test := range tests {
+		palindrome = Palindrome{Phrase: test.phrase, Chinese: test.mode}
+		Expect(t, palindrome.Validate(), nil)
+		Expect(t, palindrome.Valid, test.expected)
+	}
+}
+
+func TestValidateToFailOnUnknownChineseMode(t *testing.T) {
+	palindrome := Palindrome{Phrase: "上海自来水来自海上", Chinese: "cantonese"}
+
+	ExpectNotNil(t, palindrome.Validate())
+}
+
+func TestSyllableUnitsToKeepHanCharactersWhole(t *testing.T) {
+	engine := &Engine{Chinese: ChinesePinyin}
+
+	units, _ := engine.units("上a海")
+	Expect(t, len(units), 3)
+	Expect(t, units[0], span{"shang", 0, 3})
+	Expect(t, units[1], span{"a", 3, 4})
+	Expect(t, units[2], span{"hai", 4, 7})
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+= Confusables
+Some characters of different scripts look the same: the Cyrillic а
+and the Latin a, or the Greek ο and the Latin o. A phrase mixing them
+looks like a palindrome but isn't validated as one, and it's stored
+next to the same phrase written in Latin letters only, as if they were
+different.
+
+The confusables option replaces each of those characters by the one it
+looks like, its prototype (see confusables_dict.go), in two places:
+
+	validation     ConfusableFolding runs after the case folding, and
+	               only folds characters out of the ASCII range, so
+	               Latin phrases are read as they are
+	duplicates     the skeleton of the phrase, as defined by UTS #39
+	               [1], is the same for phrases that look the same. A
+	               phrase is a duplicate of any palindrome stored with
+	               the same skeleton
+
+As the case folding leaves no upper case letter for it, the validation
+reads the table in lower case: an upper case character is folded by its
+lower case, into the lower case of its prototype, unless the lower case
+one has a prototype of its own. So the Cyrillic Н and н are both read
+as h, while the Greek Ι is read as ι, which looks like an i.
+
+The table is a hand-picked subset of the UTS #39 data, not the whole of
+it (see confusables_dict.go). Characters left out of it are compared as
+they are, and the skeleton is only the one of UTS #39 for phrases made
+of the characters it lists.
+
+Without the option, only the same phrase is a duplicate, which the
+unique index on the phrase finds. Every palindrome is stored with its
+skeleton, though, so phrases validated with confusables are found to be
+duplicates of the ones stored before them:
+
+	key        skeleton of the palindromes validated with confusables.
+	           Unique, so two of them looking alike are never stored,
+	           even when submitted at the same time
+	lookalike  skeleton of the other palindromes. Those may look alike,
+	           so it can't be unique: it's looked up before inserting
+
+A phrase validated with confusables submitted at the same time as a
+lookalike one without them may then be stored along with it.
+
+= References
+[1] https://www.unicode.org/reports/tr39/#Confusable_Detection
+*/
+
+package main
+
+import (
+	"strings"
+	"unicode"
+
+	// Third party packages
+	"golang.org/x/text/unicode/norm"
+)
+
+var ConfusableFolding Normalizer = NormalizerFunc(func(s string) string {
+	return foldConfusables(s, lowerConfusables, func(r rune) bool {
+		return r > unicode.MaxASCII
+	})
+})
+
+// The confusables table as read after the case folding
+var lowerConfusables = lowerCaseConfusables(confusables)
+
+/*
+Returns the table with the upper case characters replaced by their
+lower case and every prototype in lower case. The lower case characters
+listed keep their own prototype.
+*/
+func lowerCaseConfusables(table map[rune]string) map[rune]string {
+	lower := make(map[rune]string, len(table))
+	for r, prototype := range table {
+		if unicode.ToLower(r) == r {
+			lower[r] = strings.ToLower(prototype)
+		}
+	}
+	for r, prototype := range table {
+		if _, ok := lower[unicode.ToLower(r)]; !ok {
+			lower[unicode.ToLower(r)] = strings.ToLower(prototype)
+		}
+	}
+
+	return lower
+}
+
+/*
+Returns the skeleton of s: s decomposed, with every confusable
+character replaced by its prototype, and decomposed again.
+*/
+func ConfusableSkeleton(s string) string {
+	folded := foldConfusables(norm.NFD.String(s), confusables, func(r rune) bool {
+		return true
+	})
+
+	return norm.NFD.String(folded)
+}
+
+/*
+Replaces the confusable characters of the table for which fold returns
+true by their prototype.
+*/
+func foldConfusables(s string, table map[rune]string, fold func(r rune) bool) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if prototype, ok := table[r]; ok && fold(r) {
+			b.WriteString(prototype)
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Embedded confusables table.
+
+Entries map a character to its prototype, as listed in the
+confusables.txt data file of UTS #39 [1]. The table is not generated
+from that file, which has several thousand entries: about a hundred of
+them were picked by hand. Only the entries whose prototype is written
+in Latin letters or digits are copied, for the letters of the scripts
+most used to spoof Latin text: Cyrillic, Greek, Armenian and Cherokee,
+along with a few Latin and ASCII lookalikes. Fullwidth and mathematical
+forms are not listed, as the compatibility folding already takes care
+of them. Any other character is its own prototype.
+
+[1] https://www.unicode.org/Public/security/latest/confusables.txt
+*/
+
+package main
+
+var confusables = map[rune]string{
+	// ASCII
+	'0': "O",
+	'1': "l",
+	'I': "l",
+	'm': "rn",
+	'|': "l",
+
+	// Latin
+	'ı': "i",
+	'ǀ': "l",
+	'ɑ': "a",
+	'ɡ': "g",
+	'ɩ': "i",
+	'ℓ': "l",
+
+	// Greek
+	'Ϳ': "J",
+	'Α': "A",
+	'Β': "B",
+	'Ε': "E",
+	'Ζ': "Z",
+	'Η': "H",
+	'Ι': "l",
+	'Κ': "K",
+	'Μ': "M",
+	'Ν': "N",
+	'Ο': "O",
+	'Ρ': "P",
+	'Τ': "T",
+	'Υ': "Y",
+	'Χ': "X",
+	'α': "a",
+	'γ': "y",
+	'ι': "i",
+	'ν': "v",
+	'ο': "o",
+	'ρ': "p",
+	'υ': "u",
+	'ϲ': "c",
+	'ϳ': "j",
+	'Ϲ': "C",
+
+	// Cyrillic
+	'Ѕ': "S",
+	'І': "l",
+	'Ј': "J",
+	'А': "A",
+	'В': "B",
+	'Е': "E",
+	'З': "3",
+	'К': "K",
+	'М': "M",
+	'Н': "H",
+	'О': "O",
+	'Р': "P",
+	'С': "C",
+	'Т': "T",
+	'У': "Y",
+	'Х': "X",
+	'а': "a",
+	'е': "e",
+	'о': "o",
+	'р': "p",
+	'с': "c",
+	'у': "y",
+	'х': "x",
+	'ѕ': "s",
+	'і': "i",
+	'ј': "j",
+	'Ѵ': "V",
+	'ѵ': "v",
+	'Ү': "Y",
+	'ү': "y",
+	'һ': "h",
+	'Ӏ': "l",
+	'ӏ': "l",
+	'ԁ': "d",
+	'Ԛ': "Q",
+	'ԛ': "q",
+	'Ԝ': "W",
+	'ԝ': "w",
+
+	// Armenian
+	'Լ': "L",
+	'Ս': "U",
+	'Տ': "S",
+	'Օ': "O",
+	'զ': "q",
+	'հ': "h",
+	'ո': "n",
+	'ս': "u",
+	'ց': "g",
+	'օ': "o",
+
+	// Cherokee
+	'Ꭲ': "T",
+	'Ꭺ': "A",
+	'Ꭻ': "J",
+	'Ꭼ': "E",
+	'Ꮃ': "W",
+	'Ꮇ': "M",
+	'Ꮋ': "H",
+	'Ꮓ': "Z",
+	'Ꮪ': "S",
+	'Ꮲ': "P",
+	'Ꮶ': "K",
+}
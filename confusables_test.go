@@ -0,0 +1,67 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestValidateToFoldConfusables(t *testing.T) {
+	// Cyrillic а and Greek ο among Latin letters
+	phrase := "Rаcecar, ο Rοtor"
+
+	palindrome := Palindrome{Phrase: "Rаcecar"}
+	palindrome.Validate()
+	Expect(t, palindrome.Valid, false)
+
+	palindrome = Palindrome{Phrase: "Rаcecar", Confusables: true}
+	palindrome.Validate()
+	Expect(t, palindrome.Valid, true)
+
+	palindrome = Palindrome{Phrase: phrase, Confusables: true}
+	palindrome.Validate()
+	Expect(t, palindrome.Valid, false)
+}
+
+func TestValidateToKeepLatinPhrasesWithConfusables(t *testing.T) {
+	// m and rn are confusable, but only for the skeleton
+	palindrome := Palindrome{Phrase: "Madam, I'm Adam", Confusables: true}
+	palindrome.Validate()
+
+	Expect(t, palindrome.Valid, true)
+}
+
+func TestSetSkeletonToRecordLookalikeSkeleton(t *testing.T) {
+	// Stored without the option
+	latin := Palindrome{Phrase: "Racecar"}
+	latin.Validate()
+	latin.SetSkeleton()
+	Expect(t, latin.Key, "")
+	Expect(t, latin.Lookalike, "Racecar")
+
+	// Unique among the ones stored with the option
+	spoofed := Palindrome{Phrase: "Rаcеcаr", Confusables: true}
+	spoofed.Validate()
+	spoofed.SetSkeleton()
+	Expect(t, spoofed.Key, latin.Lookalike)
+	Expect(t, spoofed.Lookalike, "")
+}
+
+func TestConfusableSkeletonToReturnPrototypes(t *testing.T) {
+	Expect(t, ConfusableSkeleton("pаypаl"), "paypal")
+	Expect(t, ConfusableSkeleton("Ηello"), "Hello")
+	Expect(t, ConfusableSkeleton("modern"), ConfusableSkeleton("rnodern"))
+	Expect(t, ConfusableSkeleton("é"), "é")
+}
+
+func TestValidateToFoldUpperCaseConfusables(t *testing.T) {
+	// Cyrillic Н and В, Greek Η and Cherokee Ꭺ
+	for _, phrase := range []string{"Нannah", "BoВ", "Ηannah", "Ꭺnna"} {
+		palindrome := Palindrome{Phrase: phrase, Confusables: true}
+		palindrome.Validate()
+		Expect(t, palindrome.Valid, true)
+	}
+
+	// The Greek ι has a prototype of its own, which Ι is read as
+	palindrome := Palindrome{Phrase: "Ιdi", Confusables: true}
+	palindrome.Validate()
+	Expect(t, palindrome.Valid, true)
+}
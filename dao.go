@@ -1,10 +1,39 @@
 package main
 
-import "gopkg.in/mgo.v2"
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// How long OpenDao waits for the database to answer
+const DialTimeout = 5 * time.Second
+
+const (
+	// Wait before OpenDao tries to connect again, doubled after each
+	// attempt that fails
+	ConnectRetryMin = 500 * time.Millisecond
+	// Longest wait between two attempts
+	ConnectRetryMax = 30 * time.Second
+)
+
+var errNotConnected = errors.New("Not connected yet")
+
+// Longest phrase stored. Along with its skeleton and its normalized form,
+// it keeps the document under the 16MB MongoDB allows
+const MaxStoredPhraseSize = 4 << 20
 
 type Dao struct {
 	Instance	*mgo.Session
 	Settings	Settings
+	// Set by OpenDao, guards the connection made in the background
+	connecting	*sync.RWMutex
+	// Closed along with the Dao, stops connecting
+	closed	chan struct{}
 }
 
 func NewDao(settings Settings) *Dao {
@@ -22,19 +51,122 @@ func NewDao(settings Settings) *Dao {
 	return dao
 }
 
+/*
+Returns a Dao that connects to the database in the background, instead
+of right away, so the server starts without it. The indexes are ensured
+and the migrations run once connected. Until then, every instance
+requested fails right away, while the connection is tried again after
+a wait growing from ConnectRetryMin to ConnectRetryMax.
+*/
+func OpenDao(settings Settings) *Dao {
+	dao := &Dao{
+		Settings:   settings,
+		connecting: new(sync.RWMutex),
+		closed:     make(chan struct{}),
+	}
+	go dao.connect()
+
+	return dao
+}
+
+/*
+Tries to connect until it works or the Dao is closed.
+*/
+func (dao *Dao) connect() {
+	wait := ConnectRetryMin
+	for {
+		err := dao.dial()
+		if err == nil {
+			return
+		}
+		log.Println("[database] Connection failed, retrying in ", wait, ": ", err)
+
+		select {
+		case <-dao.closed:
+			return
+		case <-time.After(wait):
+		}
+		if wait *= 2; wait > ConnectRetryMax {
+			wait = ConnectRetryMax
+		}
+	}
+}
+
+func (dao *Dao) dial() error {
+	session, err := mgo.DialWithTimeout(dao.Settings.HostName, DialTimeout)
+	if err != nil {
+		return err
+	}
+	session.SetMode(mgo.Monotonic, true)
+
+	connected := &Dao{Instance: session, Settings: dao.Settings}
+	if err := connected.EnsureIndex(); err != nil {
+		session.Close()
+		return err
+	}
+
+	dao.connecting.Lock()
+	defer dao.connecting.Unlock()
+	select {
+	case <-dao.closed:
+		// Closed while connecting
+		session.Close()
+	default:
+		dao.Instance = session
+	}
+
+	return nil
+}
+
 func (dao *Dao) Close() {
-	dao.Instance.Close()
+	if dao.connecting != nil {
+		dao.connecting.Lock()
+		defer dao.connecting.Unlock()
+		select {
+		case <-dao.closed:
+			return
+		default:
+			close(dao.closed)
+		}
+	}
+
+	if dao.Instance != nil {
+		dao.Instance.Close()
+	}
 }
 
-func (dao *Dao) GetInstance() *Dao {
-	return &Dao{dao.Instance.Copy(), dao.Settings}
+func (dao *Dao) GetInstance() (*Dao, error) {
+	if dao.connecting != nil {
+		dao.connecting.RLock()
+		defer dao.connecting.RUnlock()
+		if dao.Instance == nil {
+			return nil, errNotConnected
+		}
+	}
+
+	return &Dao{Instance: dao.Instance.Copy(), Settings: dao.Settings}, nil
 }
 
 func (dao *Dao) Database() *mgo.Database {
 	return dao.Instance.DB(dao.Settings.DbName)
 }
 
-func (dao *Dao) EnsureIndex() {
+// Indexes backing the filters and sorts of the list. See filters.go
+var ListIndexes = []mgo.Index{
+	{Key: []string{"valid", "_id"}, Background: true},
+	{Key: []string{"length", "_id"}, Background: true},
+	{Key: []string{"phrase", "_id"}, Background: true},
+	{Key: []string{"language", "_id"}, Background: true},
+	{Key: []string{"unit", "_id"}, Background: true},
+}
+
+func (dao *Dao) EnsureIndex() error {
+	// Palindromes stored by older versions are brought up to date
+	// first. See migrations.go
+	if err := dao.Migrate(); err != nil {
+		return err
+	}
+
 	c := dao.Database().C("palindromes")
 
 	index := mgo.Index{
@@ -46,7 +178,102 @@ func (dao *Dao) EnsureIndex() {
 	}
 	err := c.EnsureIndex(index)
 	if err != nil {
-		panic(err)
+		return err
+	}
+
+	// Phrases validated with confusables are duplicates of the ones
+	// that look like them. See confusables.go
+	index.Key = []string{"key"}
+	err = c.EnsureIndex(index)
+	if err != nil {
+		return err
+	}
+	err = c.EnsureIndex(mgo.Index{Key: []string{"lookalike"}, Background: true})
+	if err != nil {
+		return err
+	}
+
+	for _, index := range ListIndexes {
+		err := c.EnsureIndex(index)
+		if err != nil {
+			return err
+		}
 	}
+
+	return nil
 }
 
+/*
+Inserts the palindromes in a single unordered bulk write, so the ones
+failing don't keep the others from being inserted. Returns the error of
+each palindrome that failed, by its position.
+*/
+func (dao *Dao) InsertAll(palindromes []Palindrome) (map[int]error, error) {
+	bulk := dao.Database().C("palindromes").Bulk()
+	bulk.Unordered()
+	for _, palindrome := range palindromes {
+		bulk.Insert(palindrome)
+	}
+
+	failures := make(map[int]error)
+	_, err := bulk.Run()
+	if err == nil {
+		return failures, nil
+	}
+
+	bulkErr, ok := err.(*mgo.BulkError)
+	if !ok {
+		return nil, err
+	}
+	for _, c := range bulkErr.Cases() {
+		if c.Index < 0 {
+			return nil, err
+		}
+		failures[c.Index] = c.Err
+	}
+
+	return failures, nil
+}
+
+/*
+Returns the ID of the palindromes stored with the given phrases, by
+phrase.
+*/
+func (dao *Dao) FindIDsByPhrase(phrases []string) (map[string]bson.ObjectId, error) {
+	return dao.findIDsBy("phrase", phrases)
+}
+
+/*
+Returns the ID of the palindromes validated with confusables stored
+with the given skeletons, by skeleton.
+*/
+func (dao *Dao) FindIDsByKey(keys []string) (map[string]bson.ObjectId, error) {
+	return dao.findIDsBy("key", keys)
+}
+
+/*
+Returns the ID of a palindrome stored without confusables with each of
+the given lookalike skeletons, by skeleton.
+*/
+func (dao *Dao) FindIDsByLookalike(lookalikes []string) (map[string]bson.ObjectId, error) {
+	return dao.findIDsBy("lookalike", lookalikes)
+}
+
+func (dao *Dao) findIDsBy(field string, values []string) (map[string]bson.ObjectId, error) {
+	var docs []bson.M
+	err := dao.Database().C("palindromes").
+		Find(bson.M{field: bson.M{"$in": values}}).
+		Select(bson.M{"_id": 1, field: 1}).
+		All(&docs)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]bson.ObjectId, len(docs))
+	for _, doc := range docs {
+		value, _ := doc[field].(string)
+		ids[value], _ = doc["_id"].(bson.ObjectId)
+	}
+
+	return ids, nil
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"testing"
+	"time"
 )
 
 func TestNewDaoToReturnObject(t *testing.T) {
@@ -21,4 +22,13 @@ func TestNewDaoToReturnNil(t *testing.T) {
 	}()
 	
 	NewDao(settings)
-}
\ No newline at end of file
+}
+func TestOpenDaoToFailRightAwayUntilConnected(t *testing.T) {
+	dao := OpenDao(Settings{HostName: "invalid.host", DbName: "test"})
+	defer dao.Close()
+
+	start := time.Now()
+	_, err := dao.GetInstance()
+	Expect(t, err, errNotConnected)
+	Expect(t, time.Since(start) < DialTimeout, true)
+}
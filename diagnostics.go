@@ -0,0 +1,213 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+= Mismatch diagnostics
+When a phrase isn't a palindrome, it's more useful to know where it
+breaks the symmetry than just knowing it does. Every unit is compared
+with the one in the mirrored position and each pair that doesn't match
+is reported, along with the position of both units in the original
+phrase (see offsets.go).
+
+A phrase of a few megabytes can have hundreds of thousands of them,
+though, which nobody reads past the first few. The validation only
+lists the first MaxMismatches pairs, from the outside in, and tells
+when there were more. Only the chunks of the phrase those pairs are in
+are normalized again to find their position.
+*/
+
+package main
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// Mismatching pairs listed by the validation at most
+const MaxMismatches = 100
+
+// Unit of a mismatching pair
+type Occurrence struct {
+	// Normalized unit
+	Text string `json:"text"`
+	// Text of the phrase the unit came from
+	Original string `json:"original"`
+	Start    Offset `json:"start"`
+	End      Offset `json:"end"`
+}
+
+type Mismatch struct {
+	Left  Occurrence `json:"left"`
+	Right Occurrence `json:"right"`
+}
+
+/*
+Lists every mismatching pair of units of the phrase, from the outside
+in. A palindrome has none.
+*/
+func (e *Engine) Mismatches(phrase string) ([]Mismatch, error) {
+	units, err := e.units(phrase)
+	if err != nil {
+		return nil, err
+	}
+
+	mismatches, _ := mismatchesIn(phrase, units, len(units))
+	return mismatches, nil
+}
+
+/*
+Lists up to max mismatching pairs of the units of the phrase, and
+reports whether there were more.
+*/
+func mismatchesIn(phrase string, units []span, max int) ([]Mismatch, bool) {
+	mismatches := []Mismatch{}
+	starts, ends := unitOffsets(phrase, units)
+	for i, j := 0, len(units)-1; i < j; i, j = i+1, j-1 {
+		if units[i].text == units[j].text {
+			continue
+		}
+		if len(mismatches) == max {
+			return mismatches, true
+		}
+
+		mismatches = append(mismatches, Mismatch{
+			Left:  occurrenceOf(phrase, units[i], starts[i], ends[i]),
+			Right: occurrenceOf(phrase, units[j], starts[j], ends[j]),
+		})
+	}
+
+	return mismatches, false
+}
+
+func occurrenceOf(phrase string, unit span, start, end Offset) Occurrence {
+	return Occurrence{
+		Text:     unit.text,
+		Original: phrase[unit.start:unit.end],
+		Start:    start,
+		End:      end,
+	}
+}
+
+/*
+Lists up to max mismatching pairs of runes of the phrase normalized in
+chunks (see offsets.go), and reports whether there were more. The
+normalized text is the one of the chunks joined.
+*/
+func (e *Engine) chunkMismatches(phrase, normalized string, chunks []span, max int) ([]Mismatch, bool, error) {
+	normalizer, err := e.normalizer()
+	if err != nil {
+		return nil, false, err
+	}
+
+	locator := newChunkLocator(e, phrase, normalized, chunks, normalizer)
+
+	mismatches := []Mismatch{}
+	for i, j := 0, len(normalized); i < j; {
+		first, sizeOfFirst := utf8.DecodeRuneInString(normalized[i:j])
+		if i+sizeOfFirst == j {
+			// The middle rune
+			break
+		}
+
+		last, sizeOfLast := utf8.DecodeLastRuneInString(normalized[i:j])
+		if first != last {
+			if len(mismatches) == max {
+				return mismatches, true, nil
+			}
+			mismatches = append(mismatches, Mismatch{
+				Left:  locator.occurrenceAt(i),
+				Right: locator.occurrenceAt(j - sizeOfLast),
+			})
+		}
+		i, j = i+sizeOfFirst, j-sizeOfLast
+	}
+
+	return mismatches, false, nil
+}
+
+// Finds where the runes of a phrase normalized in chunks came from
+type chunkLocator struct {
+	engine     *Engine
+	phrase     string
+	chunks     []span
+	normalizer Normalizer
+	normalized string
+	// Where each chunk starts in the normalized text, and in runes of
+	// the phrase
+	textStarts, runeStarts []int
+	// Segments of the chunks normalized again so far
+	segments map[int][]span
+}
+
+func newChunkLocator(e *Engine, phrase, normalized string, chunks []span, normalizer Normalizer) *chunkLocator {
+	locator := &chunkLocator{
+		engine:     e,
+		phrase:     phrase,
+		chunks:     chunks,
+		normalizer: normalizer,
+		normalized: normalized,
+		textStarts: make([]int, len(chunks)),
+		runeStarts: make([]int, len(chunks)),
+		segments:   make(map[int][]span),
+	}
+
+	text, runes := 0, 0
+	for k, chunk := range chunks {
+		locator.textStarts[k], locator.runeStarts[k] = text, runes
+		text += len(chunk.text)
+		runes += utf8.RuneCountInString(phrase[chunk.start:chunk.end])
+	}
+
+	return locator
+}
+
+/*
+Returns the occurrence of the rune at the byte position pos of the
+normalized text.
+*/
+func (l *chunkLocator) occurrenceAt(pos int) Occurrence {
+	k := sort.Search(len(l.chunks), func(k int) bool {
+		return k+1 == len(l.chunks) || l.textStarts[k+1] > pos
+	})
+	chunk := l.chunks[k]
+
+	segments, ok := l.segments[k]
+	if !ok {
+		segments = l.engine.segmentSpans(l.phrase, chunk.start, chunk.end, l.normalizer, nil)
+		l.segments[k] = segments
+	}
+
+	// The segments give the same text as the chunk they're in
+	offset := l.textStarts[k]
+	segment := span{start: chunk.start, end: chunk.end}
+	for _, s := range segments {
+		segment = s
+		if pos < offset+len(s.text) {
+			break
+		}
+		offset += len(s.text)
+	}
+
+	r, _ := utf8.DecodeRuneInString(l.normalized[pos:])
+	start := l.runeStarts[k] + utf8.RuneCountInString(l.phrase[chunk.start:segment.start])
+	return Occurrence{
+		Text:     string(r),
+		Original: l.phrase[segment.start:segment.end],
+		Start:    Offset{Byte: segment.start, Rune: start},
+		End: Offset{
+			Byte: segment.end,
+			Rune: start + utf8.RuneCountInString(l.phrase[segment.start:segment.end]),
+		},
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMismatchesToReturnOffsetsInOriginalPhrase(t *testing.T) {
+	mismatches, err := new(Engine).Mismatches("Ôtimo, omitá!")
+
+	Expect(t, err, nil)
+	Expect(t, len(mismatches), 1)
+	Expect(t, mismatches[0].Left, Occurrence{
+		Text:     "o",
+		Original: "Ô",
+		Start:    Offset{0, 0},
+		End:      Offset{2, 1},
+	})
+	Expect(t, mismatches[0].Right, Occurrence{
+		Text:     "a",
+		Original: "á",
+		Start:    Offset{12, 11},
+		End:      Offset{14, 12},
+	})
+}
+
+func TestMismatchesToListEveryPair(t *testing.T) {
+	mismatches, _ := new(Engine).Mismatches("abcxba")
+
+	Expect(t, len(mismatches), 1)
+	Expect(t, mismatches[0].Left.Text, "c")
+	Expect(t, mismatches[0].Right.Text, "x")
+
+	mismatches, _ = new(Engine).Mismatches("abcd")
+	Expect(t, len(mismatches), 2)
+}
+
+func TestMismatchesToReturnNoneForPalindromes(t *testing.T) {
+	mismatches, _ := new(Engine).Mismatches("Was it a cat I saw?")
+
+	Expect(t, len(mismatches), 0)
+}
+
+func TestValidateToKeepMismatches(t *testing.T) {
+	palindrome := Palindrome{Phrase: "Fall leaves after leaves fall"}
+	palindrome.Validate()
+
+	Expect(t, palindrome.Valid, false)
+	Expect(t, len(palindrome.Mismatches) > 0, true)
+
+	palindrome.Unit = UnitWord
+	palindrome.Validate()
+
+	Expect(t, palindrome.Valid, true)
+	Expect(t, len(palindrome.Mismatches), 0)
+}
+
+func TestEngineValidateToLocateMismatchesInChunks(t *testing.T) {
+	// Longer than a chunk, with mismatches around the chunk boundaries
+	long := strings.Repeat("Dábale arroz, ", normalizedChunkSize/8)
+	phrases := []string{
+		"Ôtimo, omitá!",
+		"ｶﾞa, Ｂ ﬁ ÅA",
+		"Ô" + long + "x" + reverse(long) + "á",
+		long + "e\u0301xe\u0300" + reverse(long),
+	}
+
+	for _, phrase := range phrases {
+		expected, _ := new(Engine).Mismatches(phrase)
+		result, err := new(Engine).Validate(phrase)
+		Expect(t, err, nil)
+		Expect(t, result.MismatchesTruncated, false)
+		Expect(t, len(result.Mismatches), len(expected))
+		for i := range expected {
+			Expect(t, result.Mismatches[i], expected[i])
+		}
+	}
+}
+
+func TestEngineValidateToTruncateMismatches(t *testing.T) {
+	phrase := strings.Repeat("ab", MaxMismatches*4)
+	expected, _ := new(Engine).Mismatches(phrase)
+
+	result, _ := new(Engine).Validate(phrase)
+	Expect(t, result.MismatchesTruncated, true)
+	Expect(t, len(result.Mismatches), MaxMismatches)
+	Expect(t, result.Mismatches[MaxMismatches-1], expected[MaxMismatches-1])
+}
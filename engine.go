@@ -0,0 +1,257 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+The Engine holds the rules a phrase is validated with. Its zero value
+validates with the default rules, so the options only need to be set
+when something else is wanted.
+
+Palindrome.Validate builds an Engine out of the attributes of the
+Palindrome, which is how the options are selected by the API clients.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"unicode/utf8"
+)
+
+type Engine struct {
+	// Replaces the pipeline built from the options below
+	Normalizer Normalizer
+	// Converts kanji to kana on Japanese phrases. Defaults to
+	// DefaultReadingConverter
+	Reading  *ReadingConverter
+	Japanese JapaneseMode
+	Korean   KoreanMode
+	Chinese  ChineseMode
+	// Rules for a group of languages. See rtl.go
+	Profile Profile
+	Unit    Unit
+	// BCP 47 tag of the language whose case mapping is used. See
+	// locale.go
+	Language string
+	// Compares compatibility forms (⁹, ｶ, ﬁ) as they are, instead of
+	// folding them into the characters they are a variant of
+	KeepCompatibility bool
+	// Compares only the consonants of the Brahmic scripts. See
+	// akshara.go
+	Skeleton bool
+	// Reads the characters that look like Latin letters as those
+	// letters. See confusables.go
+	Confusables bool
+	// Goroutines validating long phrases at once. See parallel.go
+	Workers int
+}
+
+/*
+Normalizes the phrase according to the engine options.
+
+Returns the normalized phrase and, for Japanese phrases, the reading
+it was normalized from.
+*/
+func (e *Engine) Normalize(phrase string) (string, string, error) {
+	normalizer, err := e.normalizer()
+	if err != nil {
+		return "", "", err
+	}
+
+	var reading string
+	if isJapanese(phrase) {
+		reading = e.converter().Convert(phrase)
+		phrase = reading
+	}
+
+	return normalizer.Normalize(phrase), reading, nil
+}
+
+/*
+Reports whether the phrase is a palindrome, along with the reading
+used for Japanese phrases. See symmetry.go for how it's compared.
+*/
+func (e *Engine) IsPalindrome(phrase string) (bool, string, error) {
+	if e.comparesInParallel(phrase) {
+		return e.isPalindromeParallel(context.Background(), phrase)
+	}
+
+	if e.readsASCIIAsIs() {
+		if valid, ok := isASCIIPalindrome(phrase); ok {
+			return valid, "", nil
+		}
+	}
+
+	characters := (e.Unit == "" || e.Unit == UnitCharacter) && e.Chinese != ChinesePinyin
+	if characters && e.Normalizer == nil && !isJapanese(phrase) {
+		normalizer, err := e.normalizer()
+		if err != nil {
+			return false, "", err
+		}
+		return isSymmetricChunks(phrase, e.segmentCache(normalizer)), "", nil
+	}
+
+	word, reading, err := e.Normalize(phrase)
+	if err != nil {
+		return false, "", err
+	}
+
+	if characters {
+		return isSymmetric(word), reading, nil
+	}
+
+	units, err := e.units(phrase)
+	if err != nil {
+		return false, "", err
+	}
+
+	return isSymmetricUnits(units), reading, nil
+}
+
+func (e *Engine) converter() *ReadingConverter {
+	if e.Reading == nil {
+		return DefaultReadingConverter
+	}
+	return e.Reading
+}
+
+func (e *Engine) normalizer() (Normalizer, error) {
+	return e.foldingNormalizer(nil)
+}
+
+/*
+Builds the normalizer of the options, adding the letter folds it
+applies to folds, unless it's nil. A custom Normalizer applies none.
+*/
+func (e *Engine) foldingNormalizer(folds *[]Fold) (Normalizer, error) {
+	if e.Normalizer != nil {
+		return e.Normalizer, nil
+	}
+
+	base, err := e.caseFolding()
+	if err != nil {
+		return nil, err
+	}
+
+	table := letterFoldsFor(e.Language)
+	letterFolding := LetterFolding(table)
+	if folds != nil {
+		letterFolding = recordingLetterFolding(table, folds)
+	}
+	base = append(base, PunctuationStripping, letterFolding)
+	if e.Confusables {
+		base = append(base, ConfusableFolding)
+	}
+
+	profile, ok := e.Profile.normalizer()
+	if !ok {
+		return nil, errors.New("Unknown profile")
+	}
+	base = append(base, profile...)
+
+	chinese, ok := e.Chinese.normalizer()
+	if !ok {
+		return nil, errors.New("Unknown Chinese mode")
+	}
+	base = append(base, chinese...)
+
+	japanese, ok := e.Japanese.normalizer()
+	if !ok {
+		return nil, errors.New("Unknown Japanese mode")
+	}
+	var normalizer Normalizer = append(append(base, e.markRemoval()), japanese...)
+
+	korean, ok := e.Korean.normalizer()
+	if !ok {
+		return nil, errors.New("Unknown Korean mode")
+	}
+	if len(korean) > 0 {
+		normalizer = append(Pipeline{normalizer}, korean...)
+	}
+
+	if e.Skeleton {
+		normalizer = Pipeline{normalizer, ConsonantSkeleton}
+	}
+
+	return normalizer, nil
+}
+
+/*
+Returns the stage removing the marks, except the ones the Japanese mode
+keeps and, in the akshara unit, the vowel signs of the Brahmic scripts,
+which are part of the aksharas.
+*/
+func (e *Engine) markRemoval() Normalizer {
+	if e.Unit == UnitAkshara {
+		return RemoveMarks(func(r rune) bool {
+			return e.Japanese.keepsMark(r) || isBrahmicSign(r)
+		})
+	}
+
+	return RemoveMarks(e.Japanese.keepsMark)
+}
+
+/*
+Returns the stages that run before the letter folds: compatibility
+folding, unless disabled, and case folding.
+*/
+func (e *Engine) caseFolding() (Pipeline, error) {
+	caseFolding, err := caseFoldingFor(e.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	var stages Pipeline
+	if !e.KeepCompatibility {
+		stages = append(stages, CompatibilityFolding)
+	}
+
+	return append(stages, caseFolding), nil
+}
+
+/*
+Returns the letter folds applied to the phrase. A custom Normalizer
+applies none.
+*/
+func (e *Engine) Folds(phrase string) ([]Fold, error) {
+	if e.Normalizer != nil {
+		return nil, nil
+	}
+
+	stages, err := e.caseFolding()
+	if err != nil {
+		return nil, err
+	}
+
+	return foldsIn(stages.Normalize(phrase), letterFoldsFor(e.Language)), nil
+}
+
+/*
+Compares runes 1st to last position up to middle position.
+*/
+func isSymmetric(word string) bool {
+	for len(word) > 0 {
+		first, sizeOfFirst := utf8.DecodeRuneInString(word)
+		if sizeOfFirst == len(word) {
+			break
+		}
+		last, sizeOfLast := utf8.DecodeLastRuneInString(word)
+		if first != last {
+			return false
+		}
+		word = word[sizeOfFirst : len(word)-sizeOfLast]
+	}
+
+	return true
+}
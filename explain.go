@@ -0,0 +1,86 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+= Explanation
+When a phrase isn't validated as expected, the first thing to know is
+what the engine made of it. The explanation shows the phrase after
+normalization and the units it was compared by. In the Korean modes
+that decompose syllable blocks, it also shows the jamo each one of them
+was decomposed into.
+*/
+
+package main
+
+// How the engine read a phrase
+type Explanation struct {
+	// Phrase after every normalization stage
+	Normalized string `json:"normalized"`
+	// Units the phrase was compared by, in order
+	Units []string `json:"units"`
+	// Syllable blocks, in the order they first appear, and their jamo
+	Decompositions []Decomposition `json:"decompositions,omitempty"`
+}
+
+type Decomposition struct {
+	Syllable string `json:"syllable"`
+	// Conjoining jamo of the syllable
+	Jamo string `json:"jamo"`
+}
+
+/*
+Explains how the phrase is read by the engine.
+*/
+func (e *Engine) Explain(phrase string) (Explanation, error) {
+	var explanation Explanation
+
+	normalized, _, err := e.Normalize(phrase)
+	if err != nil {
+		return explanation, err
+	}
+	explanation.Normalized = normalized
+
+	units, err := e.units(phrase)
+	if err != nil {
+		return explanation, err
+	}
+	explanation.Units = make([]string, len(units))
+	for i, unit := range units {
+		explanation.Units[i] = unit.text
+	}
+
+	if e.Korean == KoreanJamo || e.Korean == KoreanIgnoreFinals {
+		explanation.Decompositions = decompositionsIn(phrase)
+	}
+
+	return explanation, nil
+}
+
+/*
+Returns the decomposition of each Hangul syllable of s.
+*/
+func decompositionsIn(s string) []Decomposition {
+	var decompositions []Decomposition
+	seen := make(map[rune]bool)
+	for _, r := range s {
+		if r < hangulSyllableBase || r > hangulSyllableLast || seen[r] {
+			continue
+		}
+
+		seen[r] = true
+		decompositions = append(decompositions, Decomposition{string(r), decomposeSyllables(string(r))})
+	}
+
+	return decompositions
+}
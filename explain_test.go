@@ -0,0 +1,24 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestExplainToShowNormalizedUnits(t *testing.T) {
+	palindrome := Palindrome{Phrase: "Race, car!"}
+	Expect(t, palindrome.Explain(), nil)
+
+	Expect(t, palindrome.Explanation.Normalized, "racecar")
+	Expect(t, len(palindrome.Explanation.Units), 7)
+	Expect(t, len(palindrome.Explanation.Decompositions), 0)
+}
+
+func TestExplainToShowJamoDecomposition(t *testing.T) {
+	palindrome := Palindrome{Phrase: "만남 만", Korean: KoreanJamo}
+	Expect(t, palindrome.Explain(), nil)
+
+	Expect(t, palindrome.Explanation.Normalized, "ㅁㅏㄴㄴㅏㅁㅁㅏㄴ")
+	Expect(t, len(palindrome.Explanation.Decompositions), 2)
+	Expect(t, palindrome.Explanation.Decompositions[0], Decomposition{"만", "\u1106\u1161\u11AB"})
+	Expect(t, palindrome.Explanation.Decompositions[1], Decomposition{"남", "\u1102\u1161\u11B7"})
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+= List filters
+The list of palindromes can be narrowed down and sorted with the query
+parameters of the request:
+
+	valid           true or false
+	created_after   creation time, inclusive, as RFC 3339 or 2006-01-02
+	created_before  creation time, exclusive, in the same formats
+	min_length      phrase length in runes, inclusive
+	max_length      phrase length in runes, inclusive
+	language        BCP 47 tag the phrase was validated with, in any
+	                case (en-us is en-US)
+	unit            unit the phrase was compared by
+	sort            created, length or phrase, prefixed with - to
+	                sort in descending order. Defaults to created
+
+The creation time isn't stored: it's the time of the ObjectId. Phrases
+stored without a unit were compared by characters. Languages are stored
+and filtered in their canonical form (see locale.go). Each filter and sort
+is backed by one of the ListIndexes (see dao.go).
+*/
+
+package main
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	// Third party packages
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Field each sort is made by
+var sortFields = map[string]string{
+	"created": "_id",
+	"length":  "length",
+	"phrase":  "phrase",
+}
+
+// Filter and order of a list of palindromes
+type ListQuery struct {
+	Filter bson.M
+	// One of the sortFields keys
+	Sort       string
+	Descending bool
+}
+
+/*
+Reads the filters and sort of the list from the query parameters.
+*/
+func ParseListQuery(query url.Values) (ListQuery, error) {
+	list := ListQuery{Filter: bson.M{}, Sort: "created"}
+
+	if value := query.Get("valid"); value != "" {
+		valid, err := strconv.ParseBool(value)
+		if err != nil {
+			return list, errors.New("Invalid valid filter")
+		}
+		list.Filter["valid"] = valid
+	}
+
+	created := bson.M{}
+	for param, operator := range map[string]string{"created_after": "$gte", "created_before": "$lt"} {
+		if value := query.Get(param); value != "" {
+			t, err := parseTime(value)
+			if err != nil {
+				return list, errors.New("Invalid " + param + " filter")
+			}
+			created[operator] = bson.NewObjectIdWithTime(t)
+		}
+	}
+	if len(created) > 0 {
+		list.Filter["_id"] = created
+	}
+
+	length := bson.M{}
+	for param, operator := range map[string]string{"min_length": "$gte", "max_length": "$lte"} {
+		if value := query.Get(param); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return list, errors.New("Invalid " + param + " filter")
+			}
+			length[operator] = n
+		}
+	}
+	if len(length) > 0 {
+		list.Filter["length"] = length
+	}
+
+	if value := query.Get("language"); value != "" {
+		language, err := canonicalLanguage(value)
+		if err != nil {
+			return list, errors.New("Invalid language filter")
+		}
+		list.Filter["language"] = language
+	}
+
+	switch unit := Unit(query.Get("unit")); unit {
+	case "":
+	case UnitCharacter:
+		list.Filter["unit"] = bson.M{"$in": []interface{}{unit, nil}}
+	case UnitGrapheme, UnitAkshara, UnitWord, UnitLine:
+		list.Filter["unit"] = unit
+	default:
+		return list, errors.New("Invalid unit filter")
+	}
+
+	if sort := query.Get("sort"); sort != "" {
+		list.Descending = strings.HasPrefix(sort, "-")
+		list.Sort = strings.TrimPrefix(sort, "-")
+		if _, ok := sortFields[list.Sort]; !ok {
+			return list, errors.New("Invalid sort")
+		}
+	}
+
+	return list, nil
+}
+
+/*
+Parses a time given either as RFC 3339 or as a date, in UTC.
+*/
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	return time.Parse("2006-01-02", value)
+}
+
+/*
+Returns the value of the sort field of the palindrome, which the
+cursors pointing at it keep. The ID is kept on its own.
+*/
+func sortValue(palindrome Palindrome, sort string) interface{} {
+	switch sort {
+	case "length":
+		return palindrome.Length
+	case "phrase":
+		return palindrome.Phrase
+	}
+
+	return nil
+}
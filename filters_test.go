@@ -0,0 +1,74 @@
+package main
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	// Third party packages
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestParseListQueryToDefaultToCreationOrder(t *testing.T) {
+	list, err := ParseListQuery(url.Values{})
+
+	Expect(t, err, nil)
+	Expect(t, len(list.Filter), 0)
+	Expect(t, list.Sort, "created")
+	Expect(t, list.Descending, false)
+}
+
+func TestParseListQueryToBuildFilters(t *testing.T) {
+	query, _ := url.ParseQuery("valid=true&min_length=5&max_length=20&language=tr&unit=word" +
+		"&created_after=2017-04-01&created_before=2017-04-13T02:30:47Z&sort=-length")
+
+	list, err := ParseListQuery(query)
+	Expect(t, err, nil)
+	Expect(t, list.Filter["valid"], true)
+	Expect(t, list.Filter["length"].(bson.M)["$gte"], 5)
+	Expect(t, list.Filter["length"].(bson.M)["$lte"], 20)
+	Expect(t, list.Filter["language"], "tr")
+	Expect(t, list.Filter["unit"], UnitWord)
+	Expect(t, list.Sort, "length")
+	Expect(t, list.Descending, true)
+
+	created := list.Filter["_id"].(bson.M)
+	Expect(t, created["$gte"].(bson.ObjectId).Time().Equal(time.Date(2017, 4, 1, 0, 0, 0, 0, time.UTC)), true)
+	Expect(t, created["$lt"].(bson.ObjectId).Time().Equal(time.Date(2017, 4, 13, 2, 30, 47, 0, time.UTC)), true)
+}
+
+func TestParseListQueryToMatchMissingUnitAsCharacter(t *testing.T) {
+	query, _ := url.ParseQuery("unit=character")
+
+	list, _ := ParseListQuery(query)
+	units := list.Filter["unit"].(bson.M)["$in"].([]interface{})
+	Expect(t, len(units), 2)
+	Expect(t, units[1], nil)
+}
+
+func TestParseListQueryToCanonicalizeLanguage(t *testing.T) {
+	query, _ := url.ParseQuery("language=EN-us")
+
+	list, err := ParseListQuery(query)
+	Expect(t, err, nil)
+	Expect(t, list.Filter["language"], "en-US")
+}
+
+func TestParseListQueryToFailOnInvalidParameters(t *testing.T) {
+	queries := []string{
+		"valid=maybe",
+		"created_after=yesterday",
+		"min_length=-1",
+		"max_length=long",
+		"sort=valid",
+		"sort=-",
+		"unit=sentence",
+		"language=not a tag",
+	}
+
+	for _, raw := range queries {
+		query, _ := url.ParseQuery(raw)
+		_, err := ParseListQuery(query)
+		ExpectNotNil(t, err)
+	}
+}
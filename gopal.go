@@ -19,17 +19,31 @@ func New() *GoPal {
 	settings := GetSettings()
 
 	instance := new(GoPal)
-	instance.Db = NewDao(settings)
-
-	// Enforce index usage
-	instance.Db.EnsureIndex()
+	// Connects in the background, so the routes not using the database
+	// work without it. See dao.go
+	instance.Db = OpenDao(settings)
 
 	var routes = Routes{
 		Route{
 			"GET", "/palindrome", PalindromeListHandler(instance.Db),
 		},
 		Route{
-			"POST", "/palindrome", PalindromeAddHandler(instance.Db),
+			"POST", "/palindrome", PalindromeAddHandler(instance.Db, settings.Workers),
+		},
+		Route{
+			"POST", "/palindrome/batch", PalindromeBatchHandler(instance.Db, settings.Workers),
+		},
+		Route{
+			"POST", "/validate", PalindromeValidateHandler(settings.Workers),
+		},
+		Route{
+			"POST", "/palindrome/longest", PalindromeLongestHandler(),
+		},
+		Route{
+			"POST", "/palindrome/number", PalindromeNumberHandler(),
+		},
+		Route{
+			"POST", "/palindrome/stream", PalindromeStreamHandler(),
 		},
 		Route{
 			"GET", "/palindrome/:id", PalindromeGetHandler(instance.Db),
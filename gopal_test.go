@@ -1,6 +1,9 @@
 package main
 
 import(
+	"bytes"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -8,4 +11,20 @@ func TestNewToReturnGoPal(t *testing.T) {
 	r := New()
 
 	ExpectNotNil(t, r)
-}
\ No newline at end of file
+}
+
+func TestNewToValidateWithoutDatabase(t *testing.T) {
+	// The database is only connected to by the routes using it
+	g := New()
+
+	r, err := http.NewRequest("POST", "/validate", bytes.NewBufferString(`{"phrase":"racecar"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	g.Router.ServeHTTP(rr, r)
+
+	Expect(t, rr.Code, http.StatusOK)
+}
@@ -0,0 +1,358 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+= Grapheme clusters
+What a reader sees as a single character can be made of several runes:
+a flag is a pair of regional indicators, a thumbs up may carry a skin
+tone modifier, and a family is a sequence of people joined by zero
+width joiners. Reversed rune by rune, 🇯🇵 becomes 🇵🇯, the flag of
+another country.
+
+The grapheme unit compares the phrase by extended grapheme clusters,
+found with the boundary rules of UAX #29 [1]. The properties the rules
+need are taken from the unicode package where a general category
+matches them, and from the tables below otherwise.
+
+= References
+[1] https://unicode.org/reports/tr29/#Grapheme_Cluster_Boundary_Rules
+*/
+
+package main
+
+import (
+	"unicode"
+)
+
+type graphemeProperty int
+
+const (
+	graphemeOther graphemeProperty = iota
+	graphemeCR
+	graphemeLF
+	graphemeControl
+	graphemeExtend
+	graphemeZWJ
+	graphemeRegionalIndicator
+	graphemePrepend
+	graphemeSpacingMark
+	graphemeL
+	graphemeV
+	graphemeT
+	graphemeLV
+	graphemeLVT
+	graphemeExtendedPictographic
+)
+
+const (
+	hangulSyllableBase  = 0xAC00
+	hangulSyllableLast  = 0xD7A3
+	hangulTrailingCount = 28
+)
+
+// Prepended concatenation marks and other runes joining the next one
+var graphemePrependTable = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x0600, 0x0605, 1},
+		{0x06DD, 0x070F, 0x070F - 0x06DD},
+		{0x0890, 0x0891, 1},
+		{0x08E2, 0x0D4E, 0x0D4E - 0x08E2},
+	},
+	R32: []unicode.Range32{
+		{0x110BD, 0x110CD, 0x110CD - 0x110BD},
+		{0x111C2, 0x111C3, 1},
+		{0x1193F, 0x11941, 2},
+		{0x11A3A, 0x11A84, 0x11A84 - 0x11A3A},
+		{0x11A85, 0x11A89, 1},
+		{0x11D46, 0x11F02, 0x11F02 - 0x11D46},
+	},
+}
+
+// Grapheme extenders other than the nonspacing and enclosing marks
+var graphemeExtendTable = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x09BE, 0x09D7, 0x09D7 - 0x09BE},
+		{0x0B3E, 0x0B57, 0x0B57 - 0x0B3E},
+		{0x0BBE, 0x0BD7, 0x0BD7 - 0x0BBE},
+		{0x0CC2, 0x0CD5, 0x0CD5 - 0x0CC2},
+		{0x0CD6, 0x0D3E, 0x0D3E - 0x0CD6},
+		{0x0D57, 0x0DCF, 0x0DCF - 0x0D57},
+		{0x0DDF, 0x1B35, 0x1B35 - 0x0DDF},
+		{0x200C, 0x302E, 0x302E - 0x200C},
+		{0x302F, 0xFF9E, 0xFF9E - 0x302F},
+		{0xFF9F, 0xFF9F, 1},
+	},
+	R32: []unicode.Range32{
+		{0x1D165, 0x1D16E, 0x1D16E - 0x1D165},
+		{0x1D16F, 0x1D172, 1},
+		// Emoji modifiers (skin tones)
+		{0x1F3FB, 0x1F3FF, 1},
+		// Tags
+		{0xE0020, 0xE007F, 1},
+	},
+}
+
+// Spacing combining marks that don't extend the grapheme before them
+var graphemeSpacingMarkExceptions = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x102B, 0x102C, 1},
+		{0x1038, 0x1062, 0x1062 - 0x1038},
+		{0x1063, 0x1064, 1},
+		{0x1067, 0x106D, 1},
+		{0x1083, 0x1087, 0x1087 - 0x1083},
+		{0x1088, 0x108C, 1},
+		{0x108F, 0x109A, 0x109A - 0x108F},
+		{0x109B, 0x109C, 1},
+		{0x1A61, 0x1A63, 2},
+		{0x1A64, 0xAA7B, 0xAA7B - 0x1A64},
+		{0xAA7D, 0xAA7D, 1},
+	},
+	R32: []unicode.Range32{
+		{0x11720, 0x11721, 1},
+	},
+}
+
+var extendedPictographicTable = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x00A9, 0x00AE, 0x00AE - 0x00A9},
+		{0x203C, 0x2049, 0x2049 - 0x203C},
+		{0x2122, 0x2139, 0x2139 - 0x2122},
+		{0x2194, 0x2199, 1},
+		{0x21A9, 0x21AA, 1},
+		{0x231A, 0x231B, 1},
+		{0x2328, 0x2388, 0x2388 - 0x2328},
+		{0x23CF, 0x23E9, 0x23E9 - 0x23CF},
+		{0x23EA, 0x23F3, 1},
+		{0x23F8, 0x23FA, 1},
+		{0x24C2, 0x25AA, 0x25AA - 0x24C2},
+		{0x25AB, 0x25B6, 0x25B6 - 0x25AB},
+		{0x25C0, 0x25FB, 0x25FB - 0x25C0},
+		{0x25FC, 0x25FE, 1},
+		{0x2600, 0x2605, 1},
+		{0x2607, 0x2612, 1},
+		{0x2614, 0x2685, 1},
+		{0x2690, 0x2705, 1},
+		{0x2708, 0x2712, 1},
+		{0x2714, 0x2716, 2},
+		{0x271D, 0x2721, 0x2721 - 0x271D},
+		{0x2728, 0x2733, 0x2733 - 0x2728},
+		{0x2734, 0x2744, 0x2744 - 0x2734},
+		{0x2747, 0x274C, 0x274C - 0x2747},
+		{0x274E, 0x2753, 0x2753 - 0x274E},
+		{0x2754, 0x2755, 1},
+		{0x2757, 0x2763, 0x2763 - 0x2757},
+		{0x2764, 0x2767, 1},
+		{0x2795, 0x2797, 1},
+		{0x27A1, 0x27B0, 0x27B0 - 0x27A1},
+		{0x27BF, 0x2934, 0x2934 - 0x27BF},
+		{0x2935, 0x2935, 1},
+		{0x2B05, 0x2B07, 1},
+		{0x2B1B, 0x2B1C, 1},
+		{0x2B50, 0x2B55, 0x2B55 - 0x2B50},
+		{0x3030, 0x303D, 0x303D - 0x3030},
+		{0x3297, 0x3299, 2},
+	},
+	R32: []unicode.Range32{
+		{0x1F000, 0x1F0FF, 1},
+		{0x1F10D, 0x1F10F, 1},
+		{0x1F12F, 0x1F16C, 0x1F16C - 0x1F12F},
+		{0x1F16D, 0x1F171, 1},
+		{0x1F17E, 0x1F17F, 1},
+		{0x1F18E, 0x1F191, 0x1F191 - 0x1F18E},
+		{0x1F192, 0x1F19A, 1},
+		{0x1F1AD, 0x1F1E5, 1},
+		{0x1F201, 0x1F20F, 1},
+		{0x1F21A, 0x1F22F, 0x1F22F - 0x1F21A},
+		{0x1F232, 0x1F23A, 1},
+		{0x1F23C, 0x1F23F, 1},
+		{0x1F249, 0x1F3FA, 1},
+		{0x1F400, 0x1F53D, 1},
+		{0x1F546, 0x1F64F, 1},
+		{0x1F680, 0x1F6FF, 1},
+		{0x1F774, 0x1F77F, 1},
+		{0x1F7D5, 0x1F7FF, 1},
+		{0x1F80C, 0x1F80F, 1},
+		{0x1F848, 0x1F84F, 1},
+		{0x1F85A, 0x1F85F, 1},
+		{0x1F888, 0x1F88F, 1},
+		{0x1F8AE, 0x1F8FF, 1},
+		{0x1F90C, 0x1F93A, 1},
+		{0x1F93C, 0x1F945, 1},
+		{0x1F947, 0x1FAFF, 1},
+		{0x1FC00, 0x1FFFD, 1},
+	},
+}
+
+// Viramas joining the consonants of a conjunct (Indic_Conjunct_Break=Linker)
+var conjunctLinkerTable = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x094D, 0x09CD, 0x09CD - 0x094D},
+		{0x0ACD, 0x0B4D, 0x0B4D - 0x0ACD},
+		{0x0C4D, 0x0D4D, 0x0D4D - 0x0C4D},
+	},
+}
+
+// Consonants taking part of a conjunct (Indic_Conjunct_Break=Consonant)
+var conjunctConsonantTable = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		// Devanagari
+		{0x0915, 0x0939, 1},
+		{0x0958, 0x095F, 1},
+		{0x0978, 0x097F, 1},
+		// Bengali
+		{0x0995, 0x09A8, 1},
+		{0x09AA, 0x09B0, 1},
+		{0x09B2, 0x09B6, 0x09B6 - 0x09B2},
+		{0x09B7, 0x09B9, 1},
+		{0x09DC, 0x09DD, 1},
+		{0x09DF, 0x09F0, 0x09F0 - 0x09DF},
+		{0x09F1, 0x09F1, 1},
+		// Gujarati
+		{0x0A95, 0x0AA8, 1},
+		{0x0AAA, 0x0AB0, 1},
+		{0x0AB2, 0x0AB3, 1},
+		{0x0AB5, 0x0AB9, 1},
+		{0x0AF9, 0x0AF9, 1},
+		// Oriya
+		{0x0B15, 0x0B28, 1},
+		{0x0B2A, 0x0B30, 1},
+		{0x0B32, 0x0B33, 1},
+		{0x0B35, 0x0B39, 1},
+		{0x0B5C, 0x0B5D, 1},
+		{0x0B5F, 0x0B71, 0x0B71 - 0x0B5F},
+		// Telugu
+		{0x0C15, 0x0C28, 1},
+		{0x0C2A, 0x0C39, 1},
+		{0x0C58, 0x0C5A, 1},
+		// Malayalam
+		{0x0D15, 0x0D3A, 1},
+	},
+}
+
+/*
+Returns the Grapheme_Cluster_Break property of the rune.
+*/
+func graphemePropertyOf(r rune) graphemeProperty {
+	switch {
+	case r == '\r':
+		return graphemeCR
+	case r == '\n':
+		return graphemeLF
+	case r == '\u200D':
+		return graphemeZWJ
+	case r >= 0x1F1E6 && r <= 0x1F1FF:
+		return graphemeRegionalIndicator
+	case r >= 0x1100 && r <= 0x115F, r >= 0xA960 && r <= 0xA97C:
+		return graphemeL
+	case r >= 0x1160 && r <= 0x11A7, r >= 0xD7B0 && r <= 0xD7C6:
+		return graphemeV
+	case r >= 0x11A8 && r <= 0x11FF, r >= 0xD7CB && r <= 0xD7FB:
+		return graphemeT
+	case r >= hangulSyllableBase && r <= hangulSyllableLast:
+		if (r-hangulSyllableBase)%hangulTrailingCount == 0 {
+			return graphemeLV
+		}
+		return graphemeLVT
+	case unicode.Is(graphemePrependTable, r):
+		return graphemePrepend
+	case unicode.In(r, unicode.Mn, unicode.Me, graphemeExtendTable):
+		return graphemeExtend
+	case unicode.In(r, unicode.Cc, unicode.Cf, unicode.Zl, unicode.Zp):
+		return graphemeControl
+	case r == '\u0E33', r == '\u0EB3':
+		return graphemeSpacingMark
+	case unicode.Is(unicode.Mc, r) && !unicode.Is(graphemeSpacingMarkExceptions, r):
+		return graphemeSpacingMark
+	case unicode.Is(extendedPictographicTable, r):
+		return graphemeExtendedPictographic
+	}
+
+	return graphemeOther
+}
+
+/*
+Returns the number of runes of the grapheme cluster runes starts with.
+*/
+func graphemeLength(runes []rune) int {
+	if len(runes) == 0 {
+		return 0
+	}
+
+	prev := graphemePropertyOf(runes[0])
+	// Regional indicators in a row, paired in flags
+	regional := 0
+	if prev == graphemeRegionalIndicator {
+		regional = 1
+	}
+	// Pictographic followed by extenders, and then by a joiner
+	pictographic := prev == graphemeExtendedPictographic
+	joined := false
+	// Consonant followed by extenders, and then by a virama
+	consonant := unicode.Is(conjunctConsonantTable, runes[0])
+	linked := false
+
+	for i := 1; i < len(runes); i++ {
+		r := runes[i]
+		next := graphemePropertyOf(r)
+
+		switch {
+		// GB3
+		case prev == graphemeCR && next == graphemeLF:
+		// GB4, GB5
+		case prev == graphemeCR || prev == graphemeLF || prev == graphemeControl,
+			next == graphemeCR || next == graphemeLF || next == graphemeControl:
+			return i
+		// GB6
+		case prev == graphemeL && (next == graphemeL || next == graphemeV || next == graphemeLV || next == graphemeLVT):
+		// GB7
+		case (prev == graphemeLV || prev == graphemeV) && (next == graphemeV || next == graphemeT):
+		// GB8
+		case (prev == graphemeLVT || prev == graphemeT) && next == graphemeT:
+		// GB9, GB9a, GB9b
+		case next == graphemeExtend, next == graphemeZWJ, next == graphemeSpacingMark, prev == graphemePrepend:
+		// GB9c
+		case linked && unicode.Is(conjunctConsonantTable, r):
+		// GB11
+		case joined && next == graphemeExtendedPictographic:
+		// GB12, GB13
+		case regional%2 == 1 && next == graphemeRegionalIndicator:
+		// GB999
+		default:
+			return i
+		}
+
+		if next == graphemeRegionalIndicator {
+			regional++
+		} else {
+			regional = 0
+		}
+
+		joined = pictographic && next == graphemeZWJ
+		pictographic = next == graphemeExtendedPictographic || (pictographic && next == graphemeExtend)
+
+		switch {
+		case unicode.Is(conjunctConsonantTable, r):
+			consonant, linked = true, false
+		case consonant && unicode.Is(conjunctLinkerTable, r):
+			linked = true
+		case consonant && (next == graphemeExtend || next == graphemeZWJ):
+		default:
+			consonant, linked = false, false
+		}
+
+		prev = next
+	}
+
+	return len(runes)
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestGraphemeLengthToFollowBoundaryRules(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected int
+	}{
+		{"", 0},
+		{"ab", 1},
+		{"\r\nx", 2},
+		{"éx", 2},
+		{"🇯🇵🇧🇷", 2},
+		{"🇯🇵🇧", 2},
+		{"👍🏽x", 2},
+		{"👨‍👩‍👧x", 5},
+		{"a‍👧", 2},
+		{"각x", 1},
+		{"\u1100\u1161\u11A8x", 3},
+		{"؀x", 2},
+		{"क्षx", 3},
+		{"नमस्ते", 1},
+	}
+
+	for _, test := range tests {
+		Expect(t, graphemeLength([]rune(test.text)), test.expected)
+	}
+}
@@ -18,8 +18,12 @@ package main
 
 import(
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"mime"
 	"net/http"
+	"strings"
 
 	// Third party packages
 	"github.com/julienschmidt/httprouter"
@@ -27,50 +31,182 @@ import(
 	"gopkg.in/mgo.v2/bson"
 )
 
-func PalindromeListHandler(dao *Dao) func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
-	return func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
-		instance := dao.GetInstance()
+/*
+Lists a page of palindromes (see pagination.go). The page is selected
+with the cursor and limit parameters, the list is filtered and sorted
+with the parameters in filters.go, and the total count of palindromes
+of the list is added with total=true. The links to the pages around it
+are given both in the body and in the Link header.
+*/
+func PalindromeListHandler(dao *Dao) func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		query := r.URL.Query()
+		cursor, err := ParseCursor(query.Get("cursor"))
+		if err != nil {
+			JSONError(w, "Invalid cursor", http.StatusBadRequest)
+			log.Println("[palindromes] Invalid cursor: ", query.Get("cursor"))
+			return
+		}
+
+		limit, err := pageLimit(r)
+		if err != nil {
+			JSONError(w, "Invalid limit", http.StatusBadRequest)
+			log.Println("[palindromes] Invalid limit: ", query.Get("limit"))
+			return
+		}
+
+		list, err := ParseListQuery(query)
+		if err != nil {
+			JSONError(w, err.Error(), http.StatusBadRequest)
+			log.Println("[palindromes] Invalid list query: ", err)
+			return
+		}
+
+		// A cursor is only valid in the list it was made for
+		if cursor.ID != "" && (cursor.Sort != list.Sort || cursor.Descending != list.Descending) {
+			JSONError(w, "Invalid cursor", http.StatusBadRequest)
+			log.Println("[palindromes] Cursor of another sort: ", cursor.Sort)
+			return
+		}
+
+		instance, ok := instanceOf(w, dao)
+		if !ok {
+			return
+		}
 		defer instance.Close()
-		c := instance.Database().C("palindromes")
 
-		var palindromes []Palindrome
-		err := c.Find(bson.M{}).All(&palindromes)
+		palindromes, more, err := instance.FindPage(list, cursor, limit)
 		if err != nil {
 			JSONError(w, "Database error", http.StatusInternalServerError)
 			log.Println("[palindromes] List fail: ", err)
 			return
 		}
 
-		JSONResponse(w, palindromes, http.StatusOK)
+		page := Page{Palindromes: palindromes}
+		var links []string
+		next, prev := pageCursors(list, cursor, palindromes, more)
+		if next != nil {
+			page.Next = pageLink(r, next, limit)
+			links = append(links, fmt.Sprintf(`<%s>; rel="next"`, page.Next))
+		}
+		if prev != nil {
+			page.Prev = pageLink(r, prev, limit)
+			links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, page.Prev))
+		}
+		if len(links) > 0 {
+			w.Header().Set("Link", strings.Join(links, ", "))
+		}
+
+		if query.Get("total") == "true" {
+			total, err := instance.Database().C("palindromes").Find(list.Filter).Count()
+			if err != nil {
+				JSONError(w, "Database error", http.StatusInternalServerError)
+				log.Println("[palindromes] Count fail: ", err)
+				return
+			}
+			page.Total = &total
+		}
+
+		JSONResponse(w, page, http.StatusOK)
 	}
 }
 
-func PalindromeAddHandler(dao *Dao) func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
-	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
-		var palindrome Palindrome
-		decoder := json.NewDecoder(r.Body)
-		err := decoder.Decode(&palindrome)
+/*
+Returns an instance of the database for the request. The error response
+is written when the database can't be reached.
+*/
+func instanceOf(w http.ResponseWriter, dao *Dao) (*Dao, bool) {
+	instance, err := dao.GetInstance()
+	if err != nil {
+		JSONError(w, "Database unavailable", http.StatusServiceUnavailable)
+		log.Println("[palindromes] Database unavailable: ", err)
+		return nil, false
+	}
+
+	return instance, true
+}
+
+/*
+Decodes the palindrome of the request and validates it with the given
+number of workers. Invalid phrases are analyzed, and the phrase is
+explained with ?explain=true. The error response is written when it
+fails.
+*/
+func validateRequest(w http.ResponseWriter, r *http.Request, workers int) (Palindrome, bool) {
+	var palindrome Palindrome
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&palindrome)
+	if err != nil {
+		JSONError(w, "Invalid request", http.StatusBadRequest)
+		log.Println("[palindromes] Invalid request: ", err)
+		return palindrome, false
+	}
+	palindrome.Workers = workers
+
+	err = palindrome.Validate()
+	if err != nil {
+		JSONError(w, "Invalid palindrome", http.StatusBadRequest)
+		log.Println("[palindromes] Validation: ", err)
+		return palindrome, false
+	}
+
+	if !palindrome.Valid {
+		err = palindrome.Analyze()
 		if err != nil {
-			JSONError(w, "Invalid request", http.StatusBadRequest)
-			log.Println("[palindromes] Invalid request: ", err)
-			return
+			log.Println("[palindromes] Analysis: ", err)
 		}
+	}
 
-		err = palindrome.Validate()
+	if r.URL.Query().Get("explain") == "true" {
+		err = palindrome.Explain()
 		if err != nil {
-			JSONError(w, "Invalid palindrome", http.StatusBadRequest)
-			log.Println("[palindromes] Validation: ", err)
+			log.Println("[palindromes] Explanation: ", err)
+		}
+	}
+
+	return palindrome, true
+}
+
+func PalindromeAddHandler(dao *Dao, workers int) func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		palindrome, ok := validateRequest(w, r, workers)
+		if !ok {
 			return
 		}
+		if len(palindrome.Phrase) > MaxStoredPhraseSize {
+			JSONError(w, "Phrase too large", http.StatusRequestEntityTooLarge)
+			log.Println("[palindromes] Phrase too large: ", len(palindrome.Phrase))
+			return
+		}
+		palindrome.SetSkeleton()
 
 		// assing id to new palindrome
 		palindrome.ID = bson.NewObjectId()
 
-		instance := dao.GetInstance()
+		instance, ok := instanceOf(w, dao)
+		if !ok {
+			return
+		}
 		defer instance.Close()
 		c := instance.Database().C("palindromes")
 
-		err = c.Insert(palindrome)
+		// Phrases looking like a stored one are duplicates of it. The
+		// ones stored with confusables are found by the unique index
+		if palindrome.Confusables {
+			ids, err := instance.FindIDsByLookalike([]string{palindrome.Key})
+			if err != nil {
+				JSONError(w, "Database error", http.StatusInternalServerError)
+				log.Println("[palindromes] Lookalike lookup: ", err)
+				return
+			}
+			if len(ids) > 0 {
+				JSONError(w, "Palindrome already exists", http.StatusAlreadyReported)
+				log.Println("[palindromes] Lookalike duplicate: ", palindrome.Key)
+				return
+			}
+		}
+
+		err := c.Insert(palindrome)
 		if err != nil {
 			if mgo.IsDup(err) {
 				JSONError(w, "Palindrome already exists", http.StatusAlreadyReported)
@@ -87,6 +223,166 @@ func PalindromeAddHandler(dao *Dao) func(w http.ResponseWriter, r *http.Request,
 	}
 }
 
+/*
+Validates the palindrome of the request like PalindromeAddHandler does,
+without storing it. It doesn't need the database, so it keeps working
+when it's unavailable.
+*/
+func PalindromeValidateHandler(workers int) func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		palindrome, ok := validateRequest(w, r, workers)
+		if !ok {
+			return
+		}
+
+		JSONResponse(w, palindrome, http.StatusOK)
+	}
+}
+
+/*
+Validates and stores the palindromes of a batch, either a JSON array or
+NDJSON (application/x-ndjson), reporting the outcome of each one. The
+palindromes are validated by the given number of workers.
+*/
+func PalindromeBatchHandler(dao *Dao, workers int) func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		var ndjson bool
+		mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		switch {
+		case err != nil, mediaType == "application/json":
+		case mediaType == "application/x-ndjson", mediaType == "application/ndjson":
+			ndjson = true
+		default:
+			JSONError(w, "Unsupported media type", http.StatusUnsupportedMediaType)
+			log.Println("[palindromes] Invalid batch type: ", r.Header.Get("Content-Type"))
+			return
+		}
+
+		body := http.MaxBytesReader(w, r.Body, MaxBatchSize)
+		items, err := decodeBatch(body, ndjson)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) || err == errTooManyItems {
+				JSONError(w, "Request too large", http.StatusRequestEntityTooLarge)
+				log.Println("[palindromes] Batch too large: ", err)
+				return
+			}
+
+			JSONError(w, "Invalid request", http.StatusBadRequest)
+			log.Println("[palindromes] Invalid batch: ", err)
+			return
+		}
+
+		palindromes, results, err := validateBatch(r.Context(), items, workers)
+		if err != nil {
+			JSONError(w, "Request cancelled", http.StatusServiceUnavailable)
+			log.Println("[palindromes] Batch validation: ", err)
+			return
+		}
+
+		instance, ok := instanceOf(w, dao)
+		if !ok {
+			return
+		}
+		defer instance.Close()
+
+		insertBatch(instance, palindromes, results)
+		JSONResponse(w, results, http.StatusOK)
+	}
+}
+
+func PalindromeLongestHandler() func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		var palindrome Palindrome
+		decoder := json.NewDecoder(r.Body)
+		err := decoder.Decode(&palindrome)
+		if err != nil {
+			JSONError(w, "Invalid request", http.StatusBadRequest)
+			log.Println("[palindromes] Invalid request: ", err)
+			return
+		}
+
+		longest, err := palindrome.Engine().Longest(palindrome.Phrase)
+		if err != nil {
+			JSONError(w, "Invalid palindrome", http.StatusBadRequest)
+			log.Println("[palindromes] Longest: ", err)
+			return
+		}
+
+		JSONResponse(w, longest, http.StatusOK)
+	}
+}
+
+func PalindromeNumberHandler() func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		var palindrome NumericPalindrome
+		decoder := json.NewDecoder(r.Body)
+		err := decoder.Decode(&palindrome)
+		if err != nil {
+			JSONError(w, "Invalid request", http.StatusBadRequest)
+			log.Println("[palindromes] Invalid request: ", err)
+			return
+		}
+
+		err = palindrome.Validate()
+		if err != nil {
+			JSONError(w, "Invalid number", http.StatusBadRequest)
+			log.Println("[palindromes] Number: ", err)
+			return
+		}
+
+		JSONResponse(w, palindrome, http.StatusOK)
+	}
+}
+
+// Largest text accepted by PalindromeStreamHandler
+const MaxStreamSize = 1 << 30
+
+/*
+Validates a text/plain body as it's uploaded, without keeping it in
+memory. Options are given in the query string, as in
+/palindrome/stream?japanese=strict&language=ja.
+*/
+func PalindromeStreamHandler() func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil || mediaType != "text/plain" {
+			JSONError(w, "Unsupported media type", http.StatusUnsupportedMediaType)
+			log.Println("[palindromes] Invalid stream type: ", r.Header.Get("Content-Type"))
+			return
+		}
+
+		query := r.URL.Query()
+		engine := &Engine{
+			Japanese:          JapaneseMode(query.Get("japanese")),
+			Korean:            KoreanMode(query.Get("korean")),
+			Chinese:           ChineseMode(query.Get("chinese")),
+			Profile:           Profile(query.Get("profile")),
+			Language:          query.Get("language"),
+			KeepCompatibility: query.Get("keep_compatibility") == "true",
+			Confusables:       query.Get("confusables") == "true",
+			Skeleton:          query.Get("skeleton") == "true",
+		}
+
+		body := http.MaxBytesReader(w, r.Body, MaxStreamSize)
+		result, err := engine.ValidateStream(body)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				JSONError(w, "Request too large", http.StatusRequestEntityTooLarge)
+				log.Println("[palindromes] Stream too large: ", err)
+				return
+			}
+
+			JSONError(w, "Invalid request", http.StatusBadRequest)
+			log.Println("[palindromes] Stream: ", err)
+			return
+		}
+
+		JSONResponse(w, result, http.StatusOK)
+	}
+}
+
 func PalindromeGetHandler(dao *Dao) func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
 	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
 		id := p.ByName("id")
@@ -96,7 +392,10 @@ func PalindromeGetHandler(dao *Dao) func(w http.ResponseWriter, r *http.Request,
 			return
 		}
 
-		instance := dao.GetInstance()
+		instance, ok := instanceOf(w, dao)
+		if !ok {
+			return
+		}
 		defer instance.Close()
 		c := instance.Database().C("palindromes")
 
@@ -128,7 +427,10 @@ func PalindromeDeleteHandler(dao *Dao) func(w http.ResponseWriter, r *http.Reque
 			return
 		}
 
-		instance := dao.GetInstance()
+		instance, ok := instanceOf(w, dao)
+		if !ok {
+			return
+		}
 		defer instance.Close()
 		c := instance.Database().C("palindromes")
 
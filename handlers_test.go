@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"bytes"
+	"strings"
 
 	"github.com/julienschmidt/httprouter"
 )
@@ -32,17 +33,149 @@ func TestPalindromeListHandlerToReturnJsonListOfPalindromes(t *testing.T) {
 		// `handler` satisfies http.Handler, so ServeHTTP method is called directly 
 		handler.ServeHTTP(rr, r)
 
-		var palindromes []Palindrome
+		var page Page
 		decoder := json.NewDecoder(rr.Body)
-		decoder.Decode(&palindromes)
+		decoder.Decode(&page)
 
 		// Status should be OK
 		Expect(t, rr.Code, http.StatusOK)
 		// Entries created for test should be here
-		Expect(t, len(palindromes), 10)
+		Expect(t, len(page.Palindromes), 10)
+		Expect(t, page.Next, "")
+		Expect(t, page.Prev, "")
 	})
 }
 
+func TestPalindromeListHandlerToPaginateWithCursor(t *testing.T) {
+	ht := new(HandlerTest)
+	ht.SetupTest( func() {
+		session := ht.Session
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			listHandler := PalindromeListHandler(session)
+			listHandler(w, r, nil)
+		})
+
+		seen := make(map[string]bool)
+		link := "/palindrome?limit=4&total=true"
+		for pages := 0; link != ""; pages++ {
+			r, err := http.NewRequest("GET", link, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, r)
+
+			var page Page
+			decoder := json.NewDecoder(rr.Body)
+			decoder.Decode(&page)
+
+			// Status should be OK, with the total count requested
+			Expect(t, rr.Code, http.StatusOK)
+			ExpectNotNil(t, page.Total)
+			Expect(t, *page.Total, 10)
+			Expect(t, pages > 0, page.Prev != "")
+			Expect(t, strings.Contains(rr.Header().Get("Link"), `rel="next"`), page.Next != "")
+
+			for _, palindrome := range page.Palindromes {
+				seen[palindrome.ID.Hex()] = true
+			}
+			link = page.Next
+		}
+
+		// Every entry is listed once, in pages of 4, 4 and 2
+		Expect(t, len(seen), 10)
+	})
+}
+
+func TestPalindromeListHandlerToFilterAndSort(t *testing.T) {
+	ht := new(HandlerTest)
+	ht.SetupTest( func() {
+		session := ht.Session
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			listHandler := PalindromeListHandler(session)
+			listHandler(w, r, nil)
+		})
+
+		// Entries have the same length, so they're sorted by ID too
+		var phrases []string
+		link := "/palindrome?valid=false&max_length=13&sort=-length&limit=3"
+		for link != "" {
+			r, err := http.NewRequest("GET", link, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, r)
+
+			var page Page
+			decoder := json.NewDecoder(rr.Body)
+			decoder.Decode(&page)
+
+			Expect(t, rr.Code, http.StatusOK)
+			for _, palindrome := range page.Palindromes {
+				phrases = append(phrases, palindrome.Phrase)
+			}
+			link = page.Next
+		}
+		Expect(t, len(phrases), 10)
+
+		// Nothing is valid
+		r, _ := http.NewRequest("GET", "/palindrome?valid=true&total=true", nil)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, r)
+
+		var page Page
+		decoder := json.NewDecoder(rr.Body)
+		decoder.Decode(&page)
+
+		Expect(t, len(page.Palindromes), 0)
+		Expect(t, *page.Total, 0)
+	})
+}
+
+func TestPalindromeListHandlerToReturnBadRequestOnInvalidCursor(t *testing.T) {
+	r, err := http.NewRequest("GET", "/palindrome?cursor=invalid", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		listHandler := PalindromeListHandler(nil)
+		listHandler(w, r, nil)
+	})
+	handler.ServeHTTP(rr, r)
+
+	// Status should be Bad Request
+	Expect(t, rr.Code, http.StatusBadRequest)
+}
+
+func TestPalindromeListHandlerToReturnServiceUnavailableWithoutDatabase(t *testing.T) {
+	dao := OpenDao(Settings{HostName: "invalid.host", DbName: "test"})
+	defer dao.Close()
+
+	// Create a request to pass to handler. Parameters are not required
+	r, err := http.NewRequest("GET", "/palindrome", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create a ResponseRecorder (which satisfies http.ResponseWriter) to record the response.
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		listHandler := PalindromeListHandler(dao)
+		listHandler(w, r, nil)
+	})
+
+	// `handler` satisfies http.Handler, so ServeHTTP method is called directly 
+	handler.ServeHTTP(rr, r)
+
+	// Status should be Service Unavailable
+	Expect(t, rr.Code, http.StatusServiceUnavailable)
+}
+
 func TestPalindromeAddHandlerToReturnCreated(t *testing.T) {
 	ht := new(HandlerTest)
 	ht.SetupTest( func() {
@@ -60,7 +193,7 @@ func TestPalindromeAddHandlerToReturnCreated(t *testing.T) {
 		// Create a ResponseRecorder (which satisfies http.ResponseWriter) to record the response.
 		rr := httptest.NewRecorder()
 		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			addHandler := PalindromeAddHandler(session)
+			addHandler := PalindromeAddHandler(session, 1)
 			addHandler(w, r, nil)
 		})
 
@@ -89,7 +222,7 @@ func TestPalindromeAddHandlerToReturnBadRequestOnInvalidRequest(t *testing.T) {
 		// Create a ResponseRecorder (which satisfies http.ResponseWriter) to record the response.
 		rr := httptest.NewRecorder()
 		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			addHandler := PalindromeAddHandler(session)
+			addHandler := PalindromeAddHandler(session, 1)
 			addHandler(w, r, nil)
 		})
 
@@ -118,7 +251,7 @@ func TestPalindromeAddHandlerToReturnBadRequestOnInvalidPhrase(t *testing.T) {
 		// Create a ResponseRecorder (which satisfies http.ResponseWriter) to record the response.
 		rr := httptest.NewRecorder()
 		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			addHandler := PalindromeAddHandler(session)
+			addHandler := PalindromeAddHandler(session, 1)
 			addHandler(w, r, nil)
 		})
 
@@ -147,7 +280,7 @@ func TestPalindromeAddHandlerToReturnBadRequestOnEmptyRequest(t *testing.T) {
 		// Create a ResponseRecorder (which satisfies http.ResponseWriter) to record the response.
 		rr := httptest.NewRecorder()
 		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			addHandler := PalindromeAddHandler(session)
+			addHandler := PalindromeAddHandler(session, 1)
 			addHandler(w, r, nil)
 		})
 
@@ -183,7 +316,7 @@ func TestPalindromeAddHandlerToReturnAlreadyReportedOnExistingPhrase(t *testing.
 		// Create a ResponseRecorder (which satisfies http.ResponseWriter) to record the response.
 		rr := httptest.NewRecorder()
 		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			addHandler := PalindromeAddHandler(session)
+			addHandler := PalindromeAddHandler(session, 1)
 			addHandler(w, r, nil)
 		})
 
@@ -195,6 +328,365 @@ func TestPalindromeAddHandlerToReturnAlreadyReportedOnExistingPhrase(t *testing.
 	})
 }
 
+func TestPalindromeAddHandlerToReturnAlreadyReportedOnLookalikePhrase(t *testing.T) {
+	ht := new(HandlerTest)
+	ht.SetupTest( func() {
+		session := ht.Session
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			addHandler := PalindromeAddHandler(session, 1)
+			addHandler(w, r, nil)
+		})
+
+		// Latin first, without confusables, then spoofed with Cyrillic letters
+		phrases := []string{
+			`{"phrase": "Racecar"}`,
+			`{"phrase": "Rаcеcаr", "confusables": true}`,
+		}
+		expected := []int{http.StatusCreated, http.StatusAlreadyReported}
+		for i, phrase := range phrases {
+			r, err := http.NewRequest("POST", "/palindrome", bytes.NewBufferString(phrase))
+			if err != nil {
+				t.Fatal(err)
+			}
+			r.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, r)
+			Expect(t, rr.Code, expected[i])
+		}
+	})
+}
+
+func TestPalindromeAddHandlerToReturnAlreadyReportedOnLookalikeConfusables(t *testing.T) {
+	ht := new(HandlerTest)
+	ht.SetupTest( func() {
+		session := ht.Session
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			addHandler := PalindromeAddHandler(session, 1)
+			addHandler(w, r, nil)
+		})
+
+		// Both with confusables, rejected by the unique index on the key
+		phrases := []string{
+			`{"phrase": "Rаcecar", "confusables": true}`,
+			`{"phrase": "Racеcar", "confusables": true}`,
+		}
+		expected := []int{http.StatusCreated, http.StatusAlreadyReported}
+		for i, phrase := range phrases {
+			r, err := http.NewRequest("POST", "/palindrome", bytes.NewBufferString(phrase))
+			if err != nil {
+				t.Fatal(err)
+			}
+			r.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, r)
+			Expect(t, rr.Code, expected[i])
+		}
+	})
+}
+
+func TestPalindromeAddHandlerToReturnRequestEntityTooLarge(t *testing.T) {
+	phrase := strings.Repeat("a", MaxStoredPhraseSize+1)
+	r, err := http.NewRequest("POST", "/palindrome", strings.NewReader(`{"phrase": "`+phrase+`"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("Content-Type", "application/json")
+
+	// Rejected before the database is used
+	rr := httptest.NewRecorder()
+	dao := OpenDao(GetSettings())
+	defer dao.Close()
+	PalindromeAddHandler(dao, 1)(rr, r, nil)
+	Expect(t, rr.Code, http.StatusRequestEntityTooLarge)
+}
+
+func TestPalindromeValidateHandlerToReturnResult(t *testing.T) {
+	var jsonStr = []byte(`{"phrase":"Was it a cat I saw?"}`)
+
+	// Create a request to pass to handler. Parameters are not required
+	r, err := http.NewRequest("POST", "/validate", bytes.NewBuffer(jsonStr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("Content-Type", "application/json")
+
+	// Create a ResponseRecorder (which satisfies http.ResponseWriter) to record the response.
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		validateHandler := PalindromeValidateHandler(1)
+		validateHandler(w, r, nil)
+	})
+
+	// `handler` satisfies http.Handler, so ServeHTTP method is called directly 
+	handler.ServeHTTP(rr, r)
+
+	var palindrome Palindrome
+	decoder := json.NewDecoder(rr.Body)
+	decoder.Decode(&palindrome)
+
+	// Status should be OK
+	Expect(t, rr.Code, http.StatusOK)
+	Expect(t, palindrome.Valid, true)
+	ExpectNotNil(t, palindrome.Result)
+	Expect(t, palindrome.Result.Normalized, "wasitacatisaw")
+}
+
+func TestPalindromeValidateHandlerToReturnBadRequestOnInvalidPhrase(t *testing.T) {
+	var jsonStr = []byte(`{"phrase": ""}`)
+
+	// Create a request to pass to handler. Parameters are not required
+	r, err := http.NewRequest("POST", "/validate", bytes.NewBuffer(jsonStr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("Content-Type", "application/json")
+
+	// Create a ResponseRecorder (which satisfies http.ResponseWriter) to record the response.
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		validateHandler := PalindromeValidateHandler(1)
+		validateHandler(w, r, nil)
+	})
+
+	// `handler` satisfies http.Handler, so ServeHTTP method is called directly 
+	handler.ServeHTTP(rr, r)
+
+	// Status should be Bad Request
+	Expect(t, rr.Code, http.StatusBadRequest)
+}
+
+func TestPalindromeBatchHandlerToReportEachItem(t *testing.T) {
+	ht := new(HandlerTest)
+	ht.SetupTest( func() {
+		session := ht.Session
+
+		var ndjson = []byte("{\"phrase\": \"Racecar\"}\n{\"phrase\": \"Racecar\"}\nnot json\n{\"phrase\": \"\"}\n")
+
+		// Create a request to pass to handler. Parameters are not required
+		r, err := http.NewRequest("POST", "/palindrome/batch", bytes.NewBuffer(ndjson))
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Header.Set("Content-Type", "application/x-ndjson")
+
+		// Create a ResponseRecorder (which satisfies http.ResponseWriter) to record the response.
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			batchHandler := PalindromeBatchHandler(session, 1)
+			batchHandler(w, r, nil)
+		})
+
+		// `handler` satisfies http.Handler, so ServeHTTP method is called directly 
+		handler.ServeHTTP(rr, r)
+
+		var results []BatchItem
+		decoder := json.NewDecoder(rr.Body)
+		decoder.Decode(&results)
+
+		// Status should be OK, with a result per item
+		Expect(t, rr.Code, http.StatusOK)
+		Expect(t, len(results), 4)
+		// Either of the repeated phrases is created, the other one is a duplicate of it
+		created, duplicate := results[0], results[1]
+		if created.Status != BatchCreated {
+			created, duplicate = duplicate, created
+		}
+		Expect(t, created.Status, BatchCreated)
+		Expect(t, duplicate.Status, BatchDuplicate)
+		Expect(t, duplicate.ID, created.ID)
+		Expect(t, results[2].Status, BatchInvalid)
+		Expect(t, results[3].Status, BatchInvalid)
+	})
+}
+
+func TestPalindromeBatchHandlerToReturnUnsupportedMediaType(t *testing.T) {
+	var jsonStr = []byte(`[{"phrase": "racecar"}]`)
+
+	// Create a request to pass to handler. Parameters are not required
+	r, err := http.NewRequest("POST", "/palindrome/batch", bytes.NewBuffer(jsonStr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("Content-Type", "text/csv")
+
+	// Create a ResponseRecorder (which satisfies http.ResponseWriter) to record the response.
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		batchHandler := PalindromeBatchHandler(nil, 1)
+		batchHandler(w, r, nil)
+	})
+
+	// `handler` satisfies http.Handler, so ServeHTTP method is called directly 
+	handler.ServeHTTP(rr, r)
+
+	// Status should be Unsupported Media Type
+	Expect(t, rr.Code, http.StatusUnsupportedMediaType)
+}
+
+func TestPalindromeLongestHandlerToReturnLongestPalindrome(t *testing.T) {
+	var jsonStr = []byte(`{"phrase":"Disse: Ótimo, omitO!"}`)
+
+	// Create a request to pass to handler. Parameters are not required
+	r, err := http.NewRequest("POST", "/palindrome/longest", bytes.NewBuffer(jsonStr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("Content-Type", "application/json")
+
+	// Create a ResponseRecorder (which satisfies http.ResponseWriter) to record the response.
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		longestHandler := PalindromeLongestHandler()
+		longestHandler(w, r, nil)
+	})
+
+	// `handler` satisfies http.Handler, so ServeHTTP method is called directly 
+	handler.ServeHTTP(rr, r)
+
+	var longest LongestPalindrome
+	decoder := json.NewDecoder(rr.Body)
+	decoder.Decode(&longest)
+
+	// Status should be OK
+	Expect(t, rr.Code, http.StatusOK)
+	Expect(t, longest.Text, "Ótimo, omitO")
+	Expect(t, longest.Start.Byte, 7)
+}
+
+func TestPalindromeLongestHandlerToReturnBadRequestOnInvalidPhrase(t *testing.T) {
+	var jsonStr = []byte(`{"phrase": ""}`)
+
+	// Create a request to pass to handler. Parameters are not required
+	r, err := http.NewRequest("POST", "/palindrome/longest", bytes.NewBuffer(jsonStr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("Content-Type", "application/json")
+
+	// Create a ResponseRecorder (which satisfies http.ResponseWriter) to record the response.
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		longestHandler := PalindromeLongestHandler()
+		longestHandler(w, r, nil)
+	})
+
+	// `handler` satisfies http.Handler, so ServeHTTP method is called directly 
+	handler.ServeHTTP(rr, r)
+
+	// Status should be Bad Request
+	Expect(t, rr.Code, http.StatusBadRequest)
+}
+
+func TestPalindromeNumberHandlerToReturnRepresentations(t *testing.T) {
+	var jsonStr = []byte(`{"number": 585, "bases": [2, 10]}`)
+
+	// Create a request to pass to handler. Parameters are not required
+	r, err := http.NewRequest("POST", "/palindrome/number", bytes.NewBuffer(jsonStr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("Content-Type", "application/json")
+
+	// Create a ResponseRecorder (which satisfies http.ResponseWriter) to record the response.
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		numberHandler := PalindromeNumberHandler()
+		numberHandler(w, r, nil)
+	})
+
+	// `handler` satisfies http.Handler, so ServeHTTP method is called directly 
+	handler.ServeHTTP(rr, r)
+
+	var palindrome NumericPalindrome
+	decoder := json.NewDecoder(rr.Body)
+	decoder.Decode(&palindrome)
+
+	// Status should be OK
+	Expect(t, rr.Code, http.StatusOK)
+	Expect(t, len(palindrome.Representations), 2)
+	Expect(t, palindrome.Representations[0].Digits, "1001001001")
+}
+
+func TestPalindromeNumberHandlerToReturnBadRequestOnInvalidNumber(t *testing.T) {
+	var jsonStr = []byte(`{"number": "five hundred"}`)
+
+	// Create a request to pass to handler. Parameters are not required
+	r, err := http.NewRequest("POST", "/palindrome/number", bytes.NewBuffer(jsonStr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("Content-Type", "application/json")
+
+	// Create a ResponseRecorder (which satisfies http.ResponseWriter) to record the response.
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		numberHandler := PalindromeNumberHandler()
+		numberHandler(w, r, nil)
+	})
+
+	// `handler` satisfies http.Handler, so ServeHTTP method is called directly 
+	handler.ServeHTTP(rr, r)
+
+	// Status should be Bad Request
+	Expect(t, rr.Code, http.StatusBadRequest)
+}
+
+func TestPalindromeStreamHandlerToValidatePlainText(t *testing.T) {
+	body := bytes.NewBufferString("Rats live on no evil star")
+
+	// Create a request to pass to handler. Parameters are not required
+	r, err := http.NewRequest("POST", "/palindrome/stream", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("Content-Type", "text/plain; charset=utf-8")
+
+	// Create a ResponseRecorder (which satisfies http.ResponseWriter) to record the response.
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		streamHandler := PalindromeStreamHandler()
+		streamHandler(w, r, nil)
+	})
+
+	// `handler` satisfies http.Handler, so ServeHTTP method is called directly 
+	handler.ServeHTTP(rr, r)
+
+	var result StreamResult
+	decoder := json.NewDecoder(rr.Body)
+	decoder.Decode(&result)
+
+	// Status should be OK
+	Expect(t, rr.Code, http.StatusOK)
+	Expect(t, result.Valid, true)
+	Expect(t, result.Bytes, int64(25))
+}
+
+func TestPalindromeStreamHandlerToReturnUnsupportedMediaType(t *testing.T) {
+	var jsonStr = []byte(`{"phrase": "racecar"}`)
+
+	// Create a request to pass to handler. Parameters are not required
+	r, err := http.NewRequest("POST", "/palindrome/stream", bytes.NewBuffer(jsonStr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("Content-Type", "application/json")
+
+	// Create a ResponseRecorder (which satisfies http.ResponseWriter) to record the response.
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		streamHandler := PalindromeStreamHandler()
+		streamHandler(w, r, nil)
+	})
+
+	// `handler` satisfies http.Handler, so ServeHTTP method is called directly 
+	handler.ServeHTTP(rr, r)
+
+	// Status should be Unsupported Media Type
+	Expect(t, rr.Code, http.StatusUnsupportedMediaType)
+}
+
 func TestPalindromeGetHandlerToReturnValidObject(t *testing.T) {
 	ht := new(HandlerTest)
 	ht.SetupTest( func() {
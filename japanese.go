@@ -0,0 +1,163 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+= Japanese comparison modes
+
+Voiced kana are composed of the base kana and a voicing mark (dakuten
+U+3099 or handakuten U+309A), both of them nonspacing marks. Removing
+every Mn mark turns ぶ into ふ, which makes がか a palindrome. Whether
+that is right depends on who's asking, so the engine supports three
+modes:
+
+	strict               voicing marks matter, katakana and hiragana differ
+	voicing-insensitive  voicing marks are ignored (the original behaviour)
+	kana-folded          voicing marks matter, katakana are read as hiragana
+
+== Small kana
+Small kana (っ, ゃ, ゅ, ょ, ぁ...) are written smaller to change how the
+previous kana is read, but they are still the same kana. Except in the
+strict mode they are folded into their full size form, so きって is
+compared as きつて.
+
+== Long vowel mark
+The long vowel mark ー extends the vowel of the kana before it. Read
+backwards it has nothing to extend, so except in the strict mode it is
+replaced by the vowel it stands for: カー => カア. A mark with no kana
+before it is kept as it is.
+*/
+
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+type JapaneseMode string
+
+const (
+	JapaneseStrict             JapaneseMode = "strict"
+	JapaneseVoicingInsensitive JapaneseMode = "voicing-insensitive"
+	JapaneseKanaFolded         JapaneseMode = "kana-folded"
+)
+
+const (
+	longVowelMark = 'ー'
+	// katakana block starts this far from the hiragana one
+	kanaOffset = 'ァ' - 'ぁ'
+)
+
+var (
+	// Folds katakana into the hiragana with the same reading
+	KanaFolding Normalizer = NormalizerFunc(func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if r >= 'ァ' && r <= 'ヶ' {
+				return r - kanaOffset
+			}
+			return r
+		}, s)
+	})
+
+	SmallKanaFolding = Transliteration(map[rune]string{
+		'ぁ': "あ", 'ぃ': "い", 'ぅ': "う", 'ぇ': "え", 'ぉ': "お",
+		'っ': "つ", 'ゃ': "や", 'ゅ': "ゆ", 'ょ': "よ", 'ゎ': "わ",
+		'ゕ': "か", 'ゖ': "け",
+		'ァ': "ア", 'ィ': "イ", 'ゥ': "ウ", 'ェ': "エ", 'ォ': "オ",
+		'ッ': "ツ", 'ャ': "ヤ", 'ュ': "ユ", 'ョ': "ヨ", 'ヮ': "ワ",
+		'ヵ': "カ", 'ヶ': "ケ",
+		'ㇰ': "ク", 'ㇱ': "シ", 'ㇲ': "ス", 'ㇳ': "ト", 'ㇴ': "ヌ",
+		'ㇵ': "ハ", 'ㇶ': "ヒ", 'ㇷ': "フ", 'ㇸ': "ヘ", 'ㇹ': "ホ",
+		'ㇺ': "ム", 'ㇻ': "ラ", 'ㇼ': "リ", 'ㇽ': "ル", 'ㇾ': "レ",
+		'ㇿ': "ロ",
+	})
+
+	LongVowelExpansion Normalizer = NormalizerFunc(expandLongVowels)
+
+	// Same as MarkRemoval, except that kana voicing marks are kept
+	VoicedMarkRemoval = RemoveMarks(isKanaVoicingMark)
+)
+
+// Vowel of each hiragana, small ones included
+var kanaVowels = map[rune]rune{}
+
+func init() {
+	rows := map[rune]string{
+		'あ': "ぁあかがさざただなはばぱまゃやらゎわ",
+		'い': "ぃいきぎしじちぢにひびぴみりゐ",
+		'う': "ぅうくぐすずっつづぬふぶぷむゅゆるゔ",
+		'え': "ぇえけげせぜてでねへべぺめれゑ",
+		'お': "ぉおこごそぞとどのほぼぽもょよろを",
+	}
+	for vowel, kana := range rows {
+		for _, r := range kana {
+			kanaVowels[r] = vowel
+		}
+	}
+}
+
+/*
+Returns the stages implementing the mode, which run once the marks are
+removed.
+*/
+func (mode JapaneseMode) normalizer() (Pipeline, bool) {
+	switch mode {
+	case "", JapaneseVoicingInsensitive:
+		return Pipeline{SmallKanaFolding, LongVowelExpansion}, true
+	case JapaneseStrict:
+		return nil, true
+	case JapaneseKanaFolded:
+		return Pipeline{KanaFolding, SmallKanaFolding, LongVowelExpansion}, true
+	}
+
+	return nil, false
+}
+
+/*
+Reports whether the mode keeps the mark r when the marks are removed,
+which the voicing marks are, unless they're ignored.
+*/
+func (mode JapaneseMode) keepsMark(r rune) bool {
+	return isKanaVoicingMark(r) && mode != "" && mode != JapaneseVoicingInsensitive
+}
+
+func expandLongVowels(s string) string {
+	var previous rune
+	return strings.Map(func(r rune) rune {
+		if r == longVowelMark {
+			if vowel, ok := vowelOf(previous); ok {
+				r = vowel
+			}
+		}
+		previous = r
+		return r
+	}, s)
+}
+
+/*
+Returns the vowel of a kana, written in the same script.
+*/
+func vowelOf(r rune) (rune, bool) {
+	if unicode.Is(unicode.Katakana, r) {
+		vowel, ok := kanaVowels[r-kanaOffset]
+		return vowel + kanaOffset, ok
+	}
+
+	vowel, ok := kanaVowels[r]
+	return vowel, ok
+}
+
+func isKanaVoicingMark(r rune) bool {
+	return r == '\u3099' || r == '\u309a'
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestJapaneseModesToHandleVoicingMarks(t *testing.T) {
+	tests := []struct {
+		mode     JapaneseMode
+		phrase   string
+		expected bool
+	}{
+		{"", "がか", true},
+		{JapaneseVoicingInsensitive, "がか", true},
+		{JapaneseStrict, "がか", false},
+		{JapaneseKanaFolded, "がか", false},
+		{JapaneseStrict, "たけやぶやけた", true},
+		{JapaneseStrict, "しなもんぱんもれもんぱんもなし", true},
+	}
+
+	for _, test := range tests {
+		palindrome := Palindrome{Phrase: test.phrase, Japanese: test.mode}
+		palindrome.Validate()
+
+		Expect(t, palindrome.Valid, test.expected)
+	}
+}
+
+func TestJapaneseModesToFoldKatakana(t *testing.T) {
+	tests := []struct {
+		mode     JapaneseMode
+		expected bool
+	}{
+		{JapaneseStrict, false},
+		{JapaneseVoicingInsensitive, false},
+		{JapaneseKanaFolded, true},
+	}
+
+	for _, test := range tests {
+		palindrome := Palindrome{Phrase: "たけやぶヤケタ", Japanese: test.mode}
+		palindrome.Validate()
+
+		Expect(t, palindrome.Valid, test.expected)
+	}
+}
+
+func TestJapaneseModesToFoldSmallKana(t *testing.T) {
+	palindrome := Palindrome{Phrase: "きつっき"}
+	palindrome.Validate()
+	Expect(t, palindrome.Valid, true)
+
+	palindrome = Palindrome{Phrase: "きつっき", Japanese: JapaneseStrict}
+	palindrome.Validate()
+	Expect(t, palindrome.Valid, false)
+}
+
+func TestJapaneseModesToExpandLongVowelMark(t *testing.T) {
+	palindrome := Palindrome{Phrase: "かーあか"}
+	palindrome.Validate()
+	Expect(t, palindrome.Valid, true)
+
+	palindrome = Palindrome{Phrase: "かーあか", Japanese: JapaneseStrict}
+	palindrome.Validate()
+	Expect(t, palindrome.Valid, false)
+}
+
+func TestLongVowelExpansionToUseVowelOfPreviousKana(t *testing.T) {
+	Expect(t, LongVowelExpansion.Normalize("カー"), "カア")
+	Expect(t, LongVowelExpansion.Normalize("ばーー"), "ばああ")
+	Expect(t, LongVowelExpansion.Normalize("ーx"), "ーx")
+}
+
+func TestKanaFoldingToReturnHiragana(t *testing.T) {
+	Expect(t, KanaFolding.Normalize("タケヤブヤケタ"), "たけやぶやけた")
+}
+
+func TestValidateToFailOnUnknownJapaneseMode(t *testing.T) {
+	palindrome := Palindrome{Phrase: "たけやぶやけた", Japanese: "unknown"}
+
+	ExpectNotNil(t, palindrome.Validate())
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+= Korean
+Hangul is written in syllable blocks, each one made of an initial
+consonant, a vowel and an optional final consonant, the jamo [1]:
+
+	만 => ㅁ ㅏ ㄴ
+
+Korean palindromes are usually read block by block, like 다시 합창합시다.
+Some of them only read the same backward jamo by jamo, like 만남
+(ㅁㅏㄴㄴㅏㅁ). The mode selects how they are compared:
+
+	syllable       each syllable block (the default)
+	jamo           each jamo
+	ignore-finals  each syllable block, without its final consonant
+
+In the jamo mode, the blocks are decomposed into conjoining jamo, which
+has different runes for a consonant in the initial and in the final
+position. The conjoining jamo are then folded into the letters of the
+Hangul Compatibility Jamo block, so ㄴ reads the same in both positions.
+
+Without final consonants, every block is a consonant followed by a
+vowel, which could never read the same backward jamo by jamo. That's
+why the final consonants are ignored by decomposing the blocks, dropping
+their final jamo and composing them back, so 간다가 reads as 가다가.
+
+= References
+[1] https://en.wikipedia.org/wiki/Hangul_Jamo_(Unicode_block)
+*/
+
+package main
+
+import (
+	"strings"
+
+	// Third party packages
+	"golang.org/x/text/unicode/norm"
+)
+
+type KoreanMode string
+
+const (
+	KoreanSyllable     KoreanMode = "syllable"
+	KoreanJamo         KoreanMode = "jamo"
+	KoreanIgnoreFinals KoreanMode = "ignore-finals"
+)
+
+const (
+	jamoInitialBase = 0x1100
+	jamoMedialBase  = 0x1161
+	jamoFinalBase   = 0x11A7 // No final consonant
+	jamoFinalLast   = 0x11C2
+	jamoMedialCount = 21
+)
+
+// Compatibility letters of the initial, medial and final jamo
+var (
+	jamoInitialLetters = []rune("ㄱㄲㄴㄷㄸㄹㅁㅂㅃㅅㅆㅇㅈㅉㅊㅋㅌㅍㅎ")
+	jamoMedialLetters  = []rune("ㅏㅐㅑㅒㅓㅔㅕㅖㅗㅘㅙㅚㅛㅜㅝㅞㅟㅠㅡㅢㅣ")
+	jamoFinalLetters   = []rune("ㄱㄲㄳㄴㄵㄶㄷㄹㄺㄻㄼㄽㄾㄿㅀㅁㅂㅄㅅㅆㅇㅈㅊㅋㅌㅍㅎ")
+)
+
+var (
+	JamoDecomposition Normalizer = NormalizerFunc(decomposeSyllables)
+
+	JamoFolding Normalizer = NormalizerFunc(func(s string) string {
+		return strings.Map(jamoLetter, s)
+	})
+
+	// Composes the blocks back once their final jamo are removed
+	FinalConsonantRemoval Normalizer = Pipeline{
+		RemoveFunc(isFinalJamo),
+		NormalizerFunc(norm.NFC.String),
+	}
+)
+
+/*
+Returns the stages implementing the mode, which run after every other
+stage. The syllable mode needs none.
+*/
+func (mode KoreanMode) normalizer() (Pipeline, bool) {
+	switch mode {
+	case "", KoreanSyllable:
+		return nil, true
+	case KoreanJamo:
+		return Pipeline{JamoDecomposition, JamoFolding}, true
+	case KoreanIgnoreFinals:
+		return Pipeline{JamoDecomposition, FinalConsonantRemoval}, true
+	}
+
+	return nil, false
+}
+
+/*
+Decomposes the Hangul syllables of s into conjoining jamo.
+*/
+func decomposeSyllables(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if r < hangulSyllableBase || r > hangulSyllableLast {
+			b.WriteRune(r)
+			continue
+		}
+
+		index := r - hangulSyllableBase
+		b.WriteRune(jamoInitialBase + index/(jamoMedialCount*hangulTrailingCount))
+		b.WriteRune(jamoMedialBase + index%(jamoMedialCount*hangulTrailingCount)/hangulTrailingCount)
+		if final := index % hangulTrailingCount; final > 0 {
+			b.WriteRune(jamoFinalBase + final)
+		}
+	}
+
+	return b.String()
+}
+
+/*
+Returns the compatibility letter of a conjoining jamo, or the rune
+itself if it's not one of the modern jamo.
+*/
+func jamoLetter(r rune) rune {
+	switch {
+	case r >= jamoInitialBase && r < jamoInitialBase+rune(len(jamoInitialLetters)):
+		return jamoInitialLetters[r-jamoInitialBase]
+	case r >= jamoMedialBase && r < jamoMedialBase+rune(len(jamoMedialLetters)):
+		return jamoMedialLetters[r-jamoMedialBase]
+	case isFinalJamo(r):
+		return jamoFinalLetters[r-jamoFinalBase-1]
+	}
+
+	return r
+}
+
+func isFinalJamo(r rune) bool {
+	return r > jamoFinalBase && r <= jamoFinalLast
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestValidateToCompareKoreanByMode(t *testing.T) {
+	tests := []struct {
+		phrase   string
+		mode     KoreanMode
+		expected bool
+	}{
+		{"다시 합창합시다", "", true},
+		{"다시 합창합시다", KoreanSyllable, true},
+		{"다시 합창합시다", KoreanJamo, false},
+		{"만남", KoreanSyllable, false},
+		{"만남", KoreanJamo, true},
+		{"만남", KoreanIgnoreFinals, false},
+		{"간다가", KoreanJamo, false},
+		{"간다가", KoreanIgnoreFinals, true},
+		{"다시 합창합시다", KoreanIgnoreFinals, true},
+		// Compatibility jamo are read as the letters they are
+		{"ㅁㅏㄴ남", KoreanJamo, true},
+	}
+
+	var palindrome Palindrome
+	for _, test := range tests {
+		palindrome = Palindrome{Phrase: test.phrase, Korean: test.mode}
+		Expect(t, palindrome.Validate(), nil)
+		Expect(t, palindrome.Valid, test.expected)
+	}
+}
+
+func TestValidateToFailOnUnknownKoreanMode(t *testing.T) {
+	palindrome := Palindrome{Phrase: "기러기", Korean: "hanja"}
+
+	ExpectNotNil(t, palindrome.Validate())
+}
+
+func TestJamoDecompositionToReturnConjoiningJamo(t *testing.T) {
+	Expect(t, JamoDecomposition.Normalize("만a"), "\u1106\u1161\u11ABa")
+	Expect(t, JamoDecomposition.Normalize("다"), "\u1103\u1161")
+	Expect(t, JamoFolding.Normalize("\u1106\u1161\u11AB"), "ㅁㅏㄴ")
+	Expect(t, FinalConsonantRemoval.Normalize("\u1106\u1161\u11AB"), "마")
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+= Letter folds
+MarkRemoval turns é into e because é decomposes into e and an accent.
+Ligatures and letters with a stroke or a slash don't decompose, so they
+are left as they are:
+
+	æ œ ß ĳ ø ł đ
+
+The letter folds replace them with the letters they're read as. Which
+letters are folded depends on the language: in Danish, Norwegian and
+Icelandic, æ and ø are letters of the alphabet on their own, so they're
+compared as they are. Languages without a table in LanguageLetterFolds
+use DefaultLetterFolds. Phrases without a language use
+UntaggedLetterFolds instead, which leave the German ß as it is: it's
+only read as ss when a language is given.
+
+The folds applied to a phrase are returned by Engine.Folds, and kept in
+the folds attribute of the palindrome.
+*/
+
+package main
+
+import (
+	"unicode/utf8"
+
+	// Third party packages
+	"golang.org/x/text/language"
+)
+
+// A letter and the text it was replaced with
+type Fold struct {
+	Letter      string `json:"letter" bson:"letter"`
+	Replacement string `json:"replacement" bson:"replacement"`
+}
+
+// Folds of lower case letters, applied in any language by default
+var DefaultLetterFolds = map[rune]string{
+	'æ': "ae",
+	'œ': "oe",
+	'ß': "ss",
+	'ĳ': "ij",
+	'ø': "o",
+	'ł': "l",
+	'đ': "d",
+	'ð': "d",
+	'þ': "th",
+	'ħ': "h",
+	'ŧ': "t",
+	'ŀ': "l",
+}
+
+// Folds applied when no language is given
+var UntaggedLetterFolds = foldsExcept('ß')
+
+// Folds of the languages whose alphabet has some of the letters above
+var LanguageLetterFolds = map[string]map[rune]string{
+	"da": foldsExcept('æ', 'ø'),
+	"fo": foldsExcept('æ', 'ø', 'ð'),
+	"is": foldsExcept('æ', 'ð', 'þ'),
+	"nb": foldsExcept('æ', 'ø'),
+	"nn": foldsExcept('æ', 'ø'),
+	"no": foldsExcept('æ', 'ø'),
+}
+
+/*
+Builds a stage that replaces the letters found in the table.
+*/
+func LetterFolding(table map[rune]string) Normalizer {
+	return Transliteration(table)
+}
+
+/*
+Builds a stage like LetterFolding that also adds the folds it applies
+to folds, in the order their letters first appear. Unlike the stock
+stages, it's not safe for concurrent use.
+*/
+func recordingLetterFolding(table map[rune]string, folds *[]Fold) Normalizer {
+	folding := LetterFolding(table)
+	seen := make(map[rune]bool)
+	return NormalizerFunc(func(s string) string {
+		for _, r := range s {
+			// Every letter folded is out of the ASCII range
+			if r < utf8.RuneSelf || seen[r] {
+				continue
+			}
+			if replacement, ok := table[r]; ok {
+				seen[r] = true
+				*folds = append(*folds, Fold{string(r), replacement})
+			}
+		}
+
+		return folding.Normalize(s)
+	})
+}
+
+/*
+Returns the letter folds of a BCP 47 tag, UntaggedLetterFolds when the
+tag is empty. Tags that can't be parsed get DefaultLetterFolds.
+*/
+func letterFoldsFor(tag string) map[rune]string {
+	if tag == "" {
+		return UntaggedLetterFolds
+	}
+
+	parsed, err := language.Parse(tag)
+	if err != nil {
+		return DefaultLetterFolds
+	}
+
+	base, _ := parsed.Base()
+	if folds, ok := LanguageLetterFolds[base.String()]; ok {
+		return folds
+	}
+
+	return DefaultLetterFolds
+}
+
+/*
+Returns the folds of the table that apply to s, in the order their
+letters first appear.
+*/
+func foldsIn(s string, table map[rune]string) []Fold {
+	var folds []Fold
+	seen := make(map[rune]bool)
+	for _, r := range s {
+		replacement, ok := table[r]
+		if !ok || seen[r] {
+			continue
+		}
+
+		seen[r] = true
+		folds = append(folds, Fold{string(r), replacement})
+	}
+
+	return folds
+}
+
+/*
+Returns a copy of DefaultLetterFolds without the given letters.
+*/
+func foldsExcept(letters ...rune) map[rune]string {
+	folds := make(map[rune]string, len(DefaultLetterFolds))
+	for letter, replacement := range DefaultLetterFolds {
+		folds[letter] = replacement
+	}
+	for _, letter := range letters {
+		delete(folds, letter)
+	}
+
+	return folds
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestValidateToFoldLetters(t *testing.T) {
+	tests := []struct {
+		phrase   string
+		language string
+		expected bool
+	}{
+		{"Cæsar, rase ac", "", true},
+		{"Œil, lieo", "fr", true},
+		{"Bob, Bøb", "sv", true},
+		{"Łapa pal", "pl", true},
+		{"Đuro rud", "hr", true},
+		{"Ĳs, sji", "nl", true},
+		// Letters on their own in the alphabet of the language
+		{"Bob, Bøb", "da", false},
+		{"Cæsar, rase ac", "nb", false},
+		{"Rør", "da", true},
+	}
+
+	var palindrome Palindrome
+	for _, test := range tests {
+		palindrome = Palindrome{Phrase: test.phrase, Language: test.language}
+		Expect(t, palindrome.Validate(), nil)
+		Expect(t, palindrome.Valid, test.expected)
+	}
+}
+
+func TestValidateToRecordFolds(t *testing.T) {
+	palindrome := Palindrome{Phrase: "Łapa pał, Æ"}
+	palindrome.Validate()
+	Expect(t, len(palindrome.Folds), 2)
+	Expect(t, palindrome.Folds[0], Fold{"ł", "l"})
+	Expect(t, palindrome.Folds[1], Fold{"æ", "ae"})
+
+	palindrome = Palindrome{Phrase: "Bøb", Language: "da"}
+	palindrome.Validate()
+	Expect(t, len(palindrome.Folds), 0)
+}
+
+func TestLetterFoldsForToFallBackToDefault(t *testing.T) {
+	Expect(t, len(letterFoldsFor("")), len(DefaultLetterFolds)-1)
+	Expect(t, len(letterFoldsFor("pl-PL")), len(DefaultLetterFolds))
+	Expect(t, len(letterFoldsFor("da-DK")), len(DefaultLetterFolds)-2)
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+= Locale case folding
+strings.ToLower maps every rune the same way, whatever the language of
+the text. That's not how some languages work:
+
+	Turkish, Azeri  I is the upper case of the dotless ı, and İ the
+	                upper case of i
+	Greek           the final sigma ς is the same letter as σ
+
+When the Engine is given a language, as a BCP 47 tag [1], the case
+mapping of that language [2] replaces CaseFolding, followed by the folds
+in localeFolds. Without a language, CaseFolding is used. Letters that
+are read as other letters, like the German ß, are folded later on (see
+letters.go).
+
+Tags are case insensitive, so palindromes are stored with the canonical
+form of theirs, which the list is filtered by: en-us is stored as en-US.
+
+= References
+[1] https://tools.ietf.org/html/bcp47
+[2] https://godoc.org/golang.org/x/text/cases
+*/
+
+package main
+
+import (
+	"errors"
+
+	// Third party packages
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"golang.org/x/text/transform"
+)
+
+// Runes compared as another text in a language, after lower case
+var localeFolds = map[string]map[rune]string{
+	"el": {'ς': "σ"},
+}
+
+/*
+Builds a stage that lower cases the text as the language does.
+*/
+func LocaleCaseFolding(tag language.Tag) Normalizer {
+	caseFolding := TransformStage(func() transform.Transformer {
+		return cases.Lower(tag)
+	})
+
+	base, _ := tag.Base()
+	if folds, ok := localeFolds[base.String()]; ok {
+		return Pipeline{caseFolding, Transliteration(folds)}
+	}
+
+	return caseFolding
+}
+
+/*
+Returns the case folding stage for a BCP 47 tag, or CaseFolding when
+the tag is empty.
+*/
+func caseFoldingFor(tag string) (Normalizer, error) {
+	if tag == "" {
+		return CaseFolding, nil
+	}
+
+	parsed, err := language.Parse(tag)
+	if err != nil {
+		return nil, errors.New("Invalid language")
+	}
+
+	return LocaleCaseFolding(parsed), nil
+}
+
+/*
+Returns the canonical form of a BCP 47 tag, or the empty tag as it is.
+*/
+func canonicalLanguage(tag string) (string, error) {
+	if tag == "" {
+		return "", nil
+	}
+
+	parsed, err := language.Parse(tag)
+	if err != nil {
+		return "", errors.New("Invalid language")
+	}
+
+	return parsed.String(), nil
+}
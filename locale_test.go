@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestValidateToFoldCaseByLanguage(t *testing.T) {
+	tests := []struct {
+		phrase   string
+		language string
+		expected bool
+	}{
+		// Turkish and Azeri dotted and dotless i
+		{"Ilık kılı", "tr", true},
+		{"Ilık kılı", "az", true},
+		{"Ilık kılı", "", false},
+		{"İki ki", "tr-TR", true},
+		{"Kiki", "tr", false},
+		// German sharp s
+		{"Maß, Sam", "de", true},
+		{"Maß, Sam", "de-AT", true},
+		{"Maß, Sam", "", false},
+		// Greek final sigma
+		{"Σοφός", "el", true},
+		{"Σοφός", "", false},
+		{"ΣΟΦΟΣ", "el", true},
+		// Without special rules, the language makes no difference
+		{"Dábale arroz a la zorra el abad", "es", true},
+		{"Dábale arroz a la zorra el abad", "", true},
+	}
+
+	var palindrome Palindrome
+	for _, test := range tests {
+		palindrome = Palindrome{Phrase: test.phrase, Language: test.language}
+		Expect(t, palindrome.Validate(), nil)
+		Expect(t, palindrome.Valid, test.expected)
+	}
+}
+
+func TestValidateToFailOnInvalidLanguage(t *testing.T) {
+	palindrome := Palindrome{Phrase: "racecar", Language: "not a tag"}
+
+	ExpectNotNil(t, palindrome.Validate())
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+= Longest palindromic substring
+A phrase that isn't a palindrome may still have one inside it. The
+longest one is found with Manacher's algorithm [1], which runs in linear
+time by reusing what it already knows about the palindromes centred
+before each position.
+
+The search is done on the units of the normalized phrase, so it follows
+the same rules Validate does. The result is then mapped back to the original
+phrase (see offsets.go). Since a segment of the phrase can't be split,
+the offsets cover every segment with at least one rune in the span.
+
+= References
+[1] https://en.wikipedia.org/wiki/Longest_palindromic_substring
+*/
+
+package main
+
+import (
+	"errors"
+)
+
+type LongestPalindrome struct {
+	// Text of the original phrase covered by the palindrome
+	Text       string `json:"text"`
+	Normalized string `json:"normalized"`
+	Start      Offset `json:"start"`
+	End        Offset `json:"end"`
+}
+
+/*
+Finds the longest palindrome in the phrase.
+*/
+func (e *Engine) Longest(phrase string) (LongestPalindrome, error) {
+	var longest LongestPalindrome
+	if len(phrase) == 0 {
+		return longest, errors.New("Invalid length")
+	}
+
+	units, err := e.units(phrase)
+	if err != nil {
+		return longest, err
+	}
+
+	lo, hi := longestPalindrome(len(units), func(i, j int) bool {
+		return units[i].text == units[j].text
+	})
+	if lo == hi {
+		return longest, nil
+	}
+
+	start, end := units[lo].start, units[hi-1].end
+	longest.Text = phrase[start:end]
+	longest.Normalized = e.Unit.join(units[lo:hi])
+	longest.Start = offsetOf(phrase, start)
+	longest.End = offsetOf(phrase, end)
+
+	return longest, nil
+}
+
+/*
+Manacher's algorithm over a sequence of n elements compared with equal.
+
+Returns the [lo, hi) bounds of the longest palindrome. When there is
+more than one of the same length, the leftmost wins.
+*/
+func longestPalindrome(n int, equal func(i, j int) bool) (int, int) {
+	bestLo, bestLen := 0, 0
+
+	// Odd lengths. odd[i] is the radius of the palindrome centred at i,
+	// centre included
+	odd := make([]int, n)
+	for i, l, r := 0, 0, -1; i < n; i++ {
+		k := 1
+		if i <= r {
+			k = minInt(odd[l+r-i], r-i+1)
+		}
+		for i-k >= 0 && i+k < n && equal(i-k, i+k) {
+			k++
+		}
+		odd[i] = k
+		if i+k-1 > r {
+			l, r = i-k+1, i+k-1
+		}
+		if 2*k-1 > bestLen {
+			bestLo, bestLen = i-k+1, 2*k-1
+		}
+	}
+
+	// Even lengths. even[i] is the radius of the palindrome centred
+	// between i-1 and i
+	even := make([]int, n)
+	for i, l, r := 0, 0, -1; i < n; i++ {
+		k := 0
+		if i <= r {
+			k = minInt(even[l+r-i+1], r-i+1)
+		}
+		for i-k-1 >= 0 && i+k < n && equal(i-k-1, i+k) {
+			k++
+		}
+		even[i] = k
+		if i+k-1 > r {
+			l, r = i-k, i+k-1
+		}
+		if 2*k > bestLen || (2*k == bestLen && i-k < bestLo) {
+			bestLo, bestLen = i-k, 2*k
+		}
+	}
+
+	return bestLo, bestLo + bestLen
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
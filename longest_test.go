@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestLongestToReturnWholePalindrome(t *testing.T) {
+	engine := new(Engine)
+
+	longest, err := engine.Longest("Was it a cat I saw?")
+	Expect(t, err, nil)
+	Expect(t, longest.Text, "Was it a cat I saw")
+	Expect(t, longest.Normalized, "wasitacatisaw")
+	Expect(t, longest.Start, Offset{0, 0})
+	Expect(t, longest.End, Offset{18, 18})
+}
+
+func TestLongestToReturnOffsetsInOriginalPhrase(t *testing.T) {
+	engine := new(Engine)
+
+	longest, _ := engine.Longest("Disse: Ótimo, omitO!")
+	Expect(t, longest.Normalized, "otimoomito")
+	Expect(t, longest.Text, "Ótimo, omitO")
+	Expect(t, longest.Start, Offset{7, 7})
+	Expect(t, longest.End, Offset{20, 19})
+}
+
+func TestLongestToMapJapaneseReadings(t *testing.T) {
+	engine := new(Engine)
+
+	longest, _ := engine.Longest("今日は竹藪焼けたよ")
+	Expect(t, longest.Normalized, "たけやふやけた")
+	Expect(t, longest.Text, "竹藪焼けた")
+	Expect(t, longest.Start, Offset{9, 3})
+}
+
+func TestLongestToFindEvenPalindromes(t *testing.T) {
+	lo, hi := longestPalindrome(6, func(i, j int) bool {
+		return "xabbay"[i] == "xabbay"[j]
+	})
+
+	Expect(t, lo, 1)
+	Expect(t, hi, 5)
+}
+
+func TestLongestToFailOnEmptyPhrase(t *testing.T) {
+	_, err := new(Engine).Longest("")
+
+	ExpectNotNil(t, err)
+}
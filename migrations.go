@@ -0,0 +1,165 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+= Migrations
+Palindromes stored by older versions miss the attributes added since,
+which the indexes and queries of the newer ones rely on. A Migration
+brings them up to date.
+
+Each migration runs once per database, when the indexes are ensured
+(see dao.go). The ones done are recorded in the migrations collection,
+so starting the server doesn't scan the palindromes again. A migration
+that fails isn't recorded, and runs again on the next start. The
+palindromes are updated by bulk writes of migrationBatchSize updates.
+*/
+
+package main
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	// Third party packages
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Palindromes updated by each bulk write of a migration
+const migrationBatchSize = 1000
+
+type Migration struct {
+	// Unique, as it's recorded once done
+	Name string
+	Run  func(c *mgo.Collection) error
+}
+
+// Migrations of the palindromes collection, in the order they run
+var Migrations = []Migration{
+	{"lookalikes", migrateLookalikes},
+	{"lengths", migrateLengths},
+	{"languages", migrateLanguages},
+}
+
+/*
+Runs the migrations not done yet on the database.
+*/
+func (dao *Dao) Migrate() error {
+	done := dao.Database().C("migrations")
+	palindromes := dao.Database().C("palindromes")
+	for _, migration := range Migrations {
+		n, err := done.FindId(migration.Name).Count()
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			continue
+		}
+
+		if err := migration.Run(palindromes); err != nil {
+			return fmt.Errorf("Migration %s: %v", migration.Name, err)
+		}
+
+		err = done.Insert(bson.M{"_id": migration.Name, "done": time.Now()})
+		if err != nil && !mgo.IsDup(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+Records the lookalike skeleton of the palindromes stored before they
+had one (see confusables.go), all of them without confusables.
+*/
+func migrateLookalikes(c *mgo.Collection) error {
+	missing := bson.M{
+		"key":       bson.M{"$exists": false},
+		"lookalike": bson.M{"$exists": false},
+	}
+	return updateEach(c, missing, "phrase", func(palindrome Palindrome) bson.M {
+		return bson.M{"lookalike": ConfusableSkeleton(palindrome.Phrase)}
+	})
+}
+
+/*
+Gives the palindromes stored before they had a length their length, which
+the list is filtered and sorted by (see filters.go).
+*/
+func migrateLengths(c *mgo.Collection) error {
+	missing := bson.M{"length": bson.M{"$exists": false}}
+	return updateEach(c, missing, "phrase", func(palindrome Palindrome) bson.M {
+		return bson.M{"length": utf8.RuneCountInString(palindrome.Phrase)}
+	})
+}
+
+/*
+Replaces the languages of the palindromes by their canonical form, which
+the list is filtered by (see locale.go). Languages that can't be parsed
+are left as they are.
+*/
+func migrateLanguages(c *mgo.Collection) error {
+	tagged := bson.M{"language": bson.M{"$exists": true}}
+	return updateEach(c, tagged, "language", func(palindrome Palindrome) bson.M {
+		language, err := canonicalLanguage(palindrome.Language)
+		if err != nil || language == palindrome.Language {
+			return nil
+		}
+		return bson.M{"language": language}
+	})
+}
+
+/*
+Sets the attributes returned by update on each palindrome matching the
+query, unless it returns nil. Only the ID and the given attribute of the
+palindromes are read.
+*/
+func updateEach(c *mgo.Collection, query bson.M, attribute string, update func(palindrome Palindrome) bson.M) error {
+	bulk, pending := c.Bulk(), 0
+	bulk.Unordered()
+
+	var palindrome Palindrome
+	iter := c.Find(query).Select(bson.M{attribute: 1}).Iter()
+	for iter.Next(&palindrome) {
+		id, set := palindrome.ID, update(palindrome)
+		palindrome = Palindrome{}
+		if set == nil {
+			continue
+		}
+
+		bulk.Update(bson.M{"_id": id}, bson.M{"$set": set})
+		pending++
+
+		if pending == migrationBatchSize {
+			if _, err := bulk.Run(); err != nil {
+				iter.Close()
+				return err
+			}
+			bulk, pending = c.Bulk(), 0
+			bulk.Unordered()
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return err
+	}
+
+	if pending > 0 {
+		_, err := bulk.Run()
+		return err
+	}
+
+	return nil
+}
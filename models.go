@@ -33,7 +33,8 @@ Examples:
 Photenic languages like Japanese require special analisis to be 
 considered as a palindrome. It's required to use their phonetic
 notation to evaluate them. In order words, ideograms representation
-(kanji) must be converted to phonetic format (hiragana) before.
+(kanji) must be converted to phonetic format (hiragana) before. That's
+what the ReadingConverter does (see reading.go).
 
 Examples:
 	竹藪焼けた => たけやぶやけた
@@ -53,6 +54,9 @@ unnormalized strings, evaluating palindromes in different languages
 require normalizing the string. To achieve we, I decided using the 
 text/transform and text/unicode/norm packages.
 
+The normalization itself is a Pipeline of stages (see normalizer.go).
+Callers may pass their own stages to Validate to change the rules.
+
 = References
 [1] https://en.wikipedia.org/wiki/Palindrome
 */
@@ -60,15 +64,9 @@ text/transform and text/unicode/norm packages.
 package main
 
 import (
-	"errors"
-	"strings"
-	"regexp"
-	"unicode"
 	"unicode/utf8"
 
 	// Third party packages
-	"golang.org/x/text/transform"
-	"golang.org/x/text/unicode/norm"
 	"gopkg.in/mgo.v2/bson"
 )
 
@@ -76,6 +74,47 @@ type Palindrome struct {
 	ID		bson.ObjectId `bson:"_id,omitempty"`
 	Phrase	string	`json:"phrase"`
 	Valid	bool	`json:"valid"`
+	// Runes of the phrase, as entered
+	Length	int	`json:"length" bson:"length"`
+	// Phonetic reading the Japanese phrase was validated with
+	Reading	string	`json:"reading,omitempty" bson:"reading,omitempty"`
+	// Rules for Japanese phrases. See japanese.go
+	Japanese JapaneseMode `json:"japanese,omitempty" bson:"japanese,omitempty"`
+	// Rules for Korean phrases. See korean.go
+	Korean	KoreanMode `json:"korean,omitempty" bson:"korean,omitempty"`
+	// Rules for Chinese phrases. See chinese.go
+	Chinese	ChineseMode `json:"chinese,omitempty" bson:"chinese,omitempty"`
+	// Rules for a group of languages, like rtl. See rtl.go
+	Profile	Profile	`json:"profile,omitempty" bson:"profile,omitempty"`
+	// Unit the phrase is compared by. See units.go
+	Unit	Unit	`json:"unit,omitempty" bson:"unit,omitempty"`
+	// BCP 47 tag of the language of the phrase. See locale.go
+	Language string `json:"language,omitempty" bson:"language,omitempty"`
+	// Letters replaced by the letter folds. See letters.go
+	Folds []Fold `json:"folds,omitempty" bson:"folds,omitempty"`
+	// Disables the folding of compatibility forms (⁹ => 9)
+	KeepCompatibility bool `json:"keep_compatibility,omitempty" bson:"keep_compatibility,omitempty"`
+	// Compares only the consonants of Brahmic scripts. See akshara.go
+	Skeleton bool `json:"skeleton,omitempty" bson:"skeleton,omitempty"`
+	// Folds characters that look alike. See confusables.go
+	Confusables bool `json:"confusables,omitempty" bson:"confusables,omitempty"`
+	// Skeleton of the phrase, the same for phrases that look alike,
+	// kept in Key when validated with confusables, which is unique, and
+	// in Lookalike otherwise. See confusables.go
+	Key       string `json:"-" bson:"key,omitempty"`
+	Lookalike string `json:"-" bson:"lookalike,omitempty"`
+	// Everything found out about the phrase. See result.go
+	Result	*ValidationResult `json:"result,omitempty" bson:"result,omitempty"`
+	// Where an invalid phrase breaks the symmetry
+	Mismatches []Mismatch `json:"mismatches,omitempty" bson:"-"`
+	// Set when only the first MaxMismatches pairs are listed
+	MismatchesTruncated bool `json:"mismatches_truncated,omitempty" bson:"-"`
+	// How far an invalid phrase is from being a palindrome
+	Analysis *Analysis `json:"analysis,omitempty" bson:"-"`
+	// How the phrase was read, when requested
+	Explanation *Explanation `json:"explain,omitempty" bson:"-"`
+	// Goroutines validating a long phrase. See parallel.go
+	Workers int `json:"-" bson:"-"`
 }
 
 /*
@@ -84,50 +123,126 @@ Validates if the instance is a valid palindrome.
 The function requires a method receiver passing a reference
 to the Palindrome object as we want to enforce the predictability
 of the behavior.
+
+The phrase is validated by Engine.Validate, and the result is kept in
+p.Result, with the verdict in p.Valid. Japanese phrases have their
+kanji replaced by the reading in hiragana first, and the reading used
+is kept in p.Reading. The letter folds applied to the phrase are kept
+in p.Folds. When the phrase is not a palindrome, the first
+MaxMismatches pairs of units that don't match are kept in p.Mismatches.
+The length of the phrase is kept in p.Length. p.Language is replaced by its
+canonical form. The phrase is normalized according to the options of
+the palindrome, unless normalizers are given, in which case they are
+chained in the order provided.
 */
-func (p *Palindrome) Validate() error {
-	word := p.Phrase
-	if len(word) == 0 {
-		return errors.New("Invalid length")
+func (p *Palindrome) Validate(normalizers ...Normalizer) error {
+	language, err := canonicalLanguage(p.Language)
+	if err != nil {
+		return err
 	}
+	p.Language = language
 
-	// Clean string before starting validation
-	word = cleanString(word)
-
-	// Compare runes 1st to last position up to middle position
-	for len(word) > 0 {
-		first, sizeOfFirst := utf8.DecodeRuneInString(word)
-		if sizeOfFirst == len(word) {
-			break 
-		}
-		last, sizeOfLast := utf8.DecodeLastRuneInString(word)
-		if first != last {
-			return nil
-		}
-		word = word[sizeOfFirst : len(word)-sizeOfLast]
+	engine := p.Engine()
+	if len(normalizers) > 0 {
+		engine.Normalizer = Pipeline(normalizers)
 	}
 
-	p.Valid = true
+	result, err := engine.Validate(p.Phrase)
+	if err != nil {
+		return err
+	}
+
+	p.Length = utf8.RuneCountInString(p.Phrase)
+	p.Unit = result.Unit
+	p.Reading = result.Reading
+	p.Folds = result.Folds
+	p.Mismatches = result.Mismatches
+	p.MismatchesTruncated = result.MismatchesTruncated
+	p.Valid = result.Valid
+	p.Result = &result
 	return nil
 }
 
 /*
-Clean up the candidate phrase before validation.
+Finds the skeleton of the phrase, which lookalike duplicates are found
+by (see confusables.go), keeping it in p.Key when it's validated with
+confusables and in p.Lookalike otherwise. Only palindromes about to be
+stored need it.
+*/
+func (p *Palindrome) SetSkeleton() {
+	p.Key, p.Lookalike = "", ConfusableSkeleton(p.Phrase)
+	if p.Confusables {
+		p.Key, p.Lookalike = p.Lookalike, ""
+	}
+}
 
-For a more detailed explanation about how the utf-8 normalization
-works, take a look here: https://blog.golang.org/normalization.
+/*
+Returns the skeleton of the phrase, wherever it's kept.
 */
-func cleanString(s string) string {
-	f := func(r rune) bool {
-		return unicode.Is(unicode.Mn, r) // Mn: nonspacing marks
+func (p *Palindrome) skeleton() string {
+	if p.Key != "" {
+		return p.Key
+	}
+	return p.Lookalike
+}
+
+/*
+Analyzes how far the phrase is from being a palindrome, keeping
+the result in p.Analysis. Phrases longer than MaxAnalysisBytes are
+left without one.
+*/
+func (p *Palindrome) Analyze() error {
+	if len(p.Phrase) > MaxAnalysisBytes {
+		return nil
 	}
 
-	// cleans up any punctuation and spaces before the normalization
-	re := regexp.MustCompile("[[:punct:]]|[[:space:]]")
-	s = re.ReplaceAllString(strings.ToLower(s), "")
+	analysis, err := p.Engine().Analyze(p.Phrase)
+	if err != nil {
+		return err
+	}
 
- 	t := transform.Chain(norm.NFD, transform.RemoveFunc(f), norm.NFC)
-	result, _, _ := transform.String(t, s)
+	p.Analysis = &analysis
+	return nil
+}
 
-	return result
+/*
+Explains how the phrase is read, keeping the explanation in
+p.Explanation.
+*/
+func (p *Palindrome) Explain() error {
+	explanation, err := p.Engine().Explain(p.Phrase)
+	if err != nil {
+		return err
+	}
+
+	p.Explanation = &explanation
+	return nil
+}
+
+/*
+Returns the Engine configured with the options of the palindrome.
+*/
+func (p *Palindrome) Engine() *Engine {
+	return &Engine{
+		Japanese:          p.Japanese,
+		Korean:            p.Korean,
+		Chinese:           p.Chinese,
+		Profile:           p.Profile,
+		Unit:              p.Unit,
+		Language:          p.Language,
+		KeepCompatibility: p.KeepCompatibility,
+		Skeleton:          p.Skeleton,
+		Confusables:       p.Confusables,
+		Workers:           p.Workers,
+	}
+}
+
+/*
+Clean up the candidate phrase before validation.
+
+For a more detailed explanation about how the utf-8 normalization
+works, take a look here: https://blog.golang.org/normalization.
+*/
+func cleanString(s string) string {
+	return DefaultNormalizer.Normalize(s)
 }
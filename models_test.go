@@ -29,36 +29,65 @@ func TestCleanStringToReturnCleanedAccentCharacters(t *testing.T) {
 }
 
 func TestValidateAssertsPalindromes(t *testing.T) {
-	phrases := []string{
-		"Go hang a salami, I'm a lasagna hog",
-		"racecar",
-		"Rats live on no evil star",
-		"Live on time, emit no evil",
-		"Mr. Owl ate my metal worm",
-		"Was it a cat I saw?",
-		"Dammit I'm Mad",
-
-		"DÁBALE ARROZ A LA ZORRA EL ABAD",
-		"ROMA ME TEM AMOR",
-		"SOCORRAM-ME, SUBI NO ÔNIBUS EM MARROCOS",
-
-		"たけやぶやけた",
-		"わたしまけましたわ",
-		"なるとをとるな",
-		"しなもんぱんもれもんぱんもなし",
-		"よのなかほかほかなのよ",
-		"たしかにかした",
+	tests := []struct {
+		phrase  string
+		profile Profile
+	}{
+		{"Go hang a salami, I'm a lasagna hog", ""},
+		{"racecar", ""},
+		{"Rats live on no evil star", ""},
+		{"Live on time, emit no evil", ""},
+		{"Mr. Owl ate my metal worm", ""},
+		{"Was it a cat I saw?", ""},
+		{"Dammit I'm Mad", ""},
+
+		{"DÁBALE ARROZ A LA ZORRA EL ABAD", ""},
+		{"ROMA ME TEM AMOR", ""},
+		{"SOCORRAM-ME, SUBI NO ÔNIBUS EM MARROCOS", ""},
+
+		{"たけやぶやけた", ""},
+		{"わたしまけましたわ", ""},
+		{"なるとをとるな", ""},
+		{"しなもんぱんもれもんぱんもなし", ""},
+		{"よのなかほかほかなのよ", ""},
+		{"たしかにかした", ""},
+		{"竹藪焼けた", ""},
+		{"私負けましたわ", ""},
+		{"確かに貸した", ""},
+
+		{"ילד כותב בתוך דלי", ProfileRTL},
+		{"מים", ProfileRTL},
+		{"אבי ביבא", ProfileRTL},
+		{"مودته تدوم لكل هول وهل كل مودته تدوم", ProfileRTL},
+		{"مـودته تـدوم لكل هـول، وهل كل مودته تدوم؟", ProfileRTL},
+		// Presentation forms, folded by the compatibility folding
+		{"\uFEDF\uFEDC\uFEDE", ""},
+		{"\uFEDF\uFEDC\uFEDE", ProfileRTL},
 	}
 
 	var palindrome Palindrome
-	for _, phrase := range phrases {
-		palindrome = Palindrome{Phrase: phrase}
+	for _, test := range tests {
+		palindrome = Palindrome{Phrase: test.phrase, Profile: test.profile}
 		palindrome.Validate()
 
 		Expect(t, palindrome.Valid, true)
 	}
 }
 
+func TestValidateToFailOnUnknownProfile(t *testing.T) {
+	palindrome := Palindrome{Phrase: "racecar", Profile: "ltr"}
+
+	ExpectNotNil(t, palindrome.Validate())
+}
+
+func TestValidateToCanonicalizeLanguage(t *testing.T) {
+	palindrome := Palindrome{Phrase: "racecar", Language: "EN-us"}
+
+	Expect(t, palindrome.Validate(), nil)
+	Expect(t, palindrome.Language, "en-US")
+	Expect(t, palindrome.Result.Options.Language, "en-US")
+}
+
 func TestValidateRefutePalindromes(t *testing.T) {
 	phrases := []string{
 		"Not a valid palindrome",
@@ -66,6 +95,8 @@ func TestValidateRefutePalindromes(t *testing.T) {
 		"Il n'y a pas qqch d'intéressant ici",
 		"Uma frase qualquer sem conexão",
 		"一期一会",
+		// Final letters, only folded by the rtl profile
+		"ילד כותב בתוך דלי",
 	}
 
 	var palindrome Palindrome
@@ -75,4 +106,38 @@ func TestValidateRefutePalindromes(t *testing.T) {
 
 		Expect(t, palindrome.Valid, false)
 	}
-}
\ No newline at end of file
+}
+
+func TestValidateToFoldCompatibilityForms(t *testing.T) {
+	tests := []struct {
+		phrase            string
+		japanese          JapaneseMode
+		profile           Profile
+		keepCompatibility bool
+		expected          bool
+	}{
+		{"2⁹92", "", "", false, true},
+		{"2⁹92", "", "", true, false},
+		{"\u212Aayak", "", "", false, true},
+		{"Ｒａｃｅｃａｒ", "", "", false, true},
+		{"Ｒａｃｅ car", "", "", false, true},
+		{"Ｒａｃｅ car", "", "", true, false},
+		{"タケヤﾌﾞヤｹﾀ", JapaneseStrict, "", false, true},
+		{"タケヤﾌﾞヤｹﾀ", JapaneseStrict, "", true, false},
+		// Presentation forms are still folded by the rtl profile
+		{"\uFEDF\uFEDC\uFEDE", "", "", true, false},
+		{"\uFEDF\uFEDC\uFEDE", "", ProfileRTL, true, true},
+	}
+
+	var palindrome Palindrome
+	for _, test := range tests {
+		palindrome = Palindrome{
+			Phrase:            test.phrase,
+			Japanese:          test.japanese,
+			Profile:           test.profile,
+			KeepCompatibility: test.keepCompatibility,
+		}
+		Expect(t, palindrome.Validate(), nil)
+		Expect(t, palindrome.Valid, test.expected)
+	}
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+A Normalizer turns a candidate phrase into the form its characters are
+compared in. Different corpora need different rules, so instead of a
+single hard-coded function the normalization is split in small stages
+that can be chained together in a Pipeline.
+
+The stock stages are:
+	CompatibilityFolding  NFKC, folding compatibility forms (⁹ => 9, ｶ => カ)
+	CaseFolding           lower case
+	PunctuationStripping  drops ASCII punctuation and white space
+	MarkRemoval           NFD => remove nonspacing marks (Mn) => NFC
+	WidthFolding          fullwidth and halfwidth forms to their canonical width
+	Transliteration       replaces runes according to a table
+	LetterFolding         ligatures and letters that don't decompose (see
+	                      letters.go)
+
+DefaultNormalizer chains the first four of them, followed by the letter
+folds used without a language. Without the compatibility and letter
+folding, that's exactly what cleanString used to do before the stages
+were split. The Engine extends it with the rules of the language
+options it's given.
+*/
+
+package main
+
+import (
+	"strings"
+	"unicode"
+
+	// Third party packages
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/width"
+)
+
+type Normalizer interface {
+	Normalize(s string) string
+}
+
+// NormalizerFunc adapts an ordinary function to the Normalizer interface.
+type NormalizerFunc func(s string) string
+
+func (f NormalizerFunc) Normalize(s string) string {
+	return f(s)
+}
+
+// Pipeline runs every stage in order, feeding each one with the output
+// of the previous stage.
+type Pipeline []Normalizer
+
+func (p Pipeline) Normalize(s string) string {
+	for _, stage := range p {
+		s = stage.Normalize(s)
+	}
+	return s
+}
+
+var (
+	// Superscripts, fullwidth and halfwidth forms and letterlike
+	// symbols are replaced by the characters they are a variant of.
+	CompatibilityFolding Normalizer = NormalizerFunc(norm.NFKC.String)
+
+	CaseFolding Normalizer = NormalizerFunc(strings.ToLower)
+
+	// Matches the same characters as "[[:punct:]]|[[:space:]]", which
+	// are ASCII only.
+	PunctuationStripping Normalizer = RemoveFunc(isASCIIPunctOrSpace)
+
+	MarkRemoval = RemoveMarks(nil)
+
+	// Halfwidth katakana keep their voicing marks as separate runes
+	// after folding, so they are composed back afterwards.
+	WidthFolding Normalizer = TransformStage(func() transform.Transformer {
+		return transform.Chain(width.Fold, norm.NFC)
+	})
+)
+
+// The base pipeline, with no rules specific to any language.
+var DefaultNormalizer = Pipeline{
+	CompatibilityFolding,
+	CaseFolding,
+	PunctuationStripping,
+	MarkRemoval,
+	LetterFolding(UntaggedLetterFolds),
+}
+
+/*
+Builds a stage that drops every rune for which f returns true.
+*/
+func RemoveFunc(f func(r rune) bool) Normalizer {
+	return NormalizerFunc(func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if f(r) {
+				return -1
+			}
+			return r
+		}, s)
+	})
+}
+
+/*
+Builds a stage that decomposes the text, removes its nonspacing marks
+and composes it back. Marks for which keep returns true are left in
+place. A nil keep removes all of them.
+*/
+func RemoveMarks(keep func(r rune) bool) Normalizer {
+	remove := isNonspacingMark
+	if keep != nil {
+		remove = func(r rune) bool {
+			return isNonspacingMark(r) && !keep(r)
+		}
+	}
+
+	// Chaining the transformers would allocate their buffers on every
+	// call, which costs more than the text of a segment
+	removal := RemoveFunc(remove)
+	return NormalizerFunc(func(s string) string {
+		return norm.NFC.String(removal.Normalize(norm.NFD.String(s)))
+	})
+}
+
+/*
+Builds a stage out of a text/transform Transformer.
+
+Transformers keep state between calls, so a new one is requested
+every time the stage runs. That keeps stages safe to share between
+goroutines.
+*/
+func TransformStage(newTransformer func() transform.Transformer) Normalizer {
+	return NormalizerFunc(func(s string) string {
+		result, _, err := transform.String(newTransformer(), s)
+		if err != nil {
+			return s
+		}
+		return result
+	})
+}
+
+/*
+Builds a stage that replaces each rune found in the table by its
+replacement. Replacements may be longer than one rune (æ => ae) or
+empty, in which case the rune is dropped.
+*/
+func Transliteration(table map[rune]string) Normalizer {
+	return NormalizerFunc(func(s string) string {
+		var b strings.Builder
+		b.Grow(len(s))
+		for _, r := range s {
+			if replacement, ok := table[r]; ok {
+				b.WriteString(replacement)
+				continue
+			}
+			b.WriteRune(r)
+		}
+		return b.String()
+	})
+}
+
+func isNonspacingMark(r rune) bool {
+	return unicode.Is(unicode.Mn, r) // Mn: nonspacing marks
+}
+
+func isASCIIPunctOrSpace(r rune) bool {
+	switch {
+	case r > unicode.MaxASCII:
+		return false
+	case r == ' ', r >= '\t' && r <= '\r':
+		return true
+	}
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestCompatibilityFoldingToReturnCanonicalForms(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"⁹", "9"},
+		{"\u212A", "K"},
+		{"Ｒａｃｅｃａｒ", "Racecar"},
+		{"ﾀｹﾔﾌﾞﾔｹﾀ", "タケヤブヤケタ"},
+	}
+
+	for _, test := range tests {
+		Expect(t, CompatibilityFolding.Normalize(test.text), test.expected)
+	}
+}
+
+func TestCaseFoldingToReturnLowercase(t *testing.T) {
+	Expect(t, CaseFolding.Normalize("RaceCar"), "racecar")
+}
+
+func TestPunctuationStrippingToRemoveASCIIPunctuationAndSpaces(t *testing.T) {
+	Expect(t, PunctuationStripping.Normalize("Was it a cat, I saw?\t$5+"), "WasitacatIsaw5")
+	// Only ASCII punctuation is stripped, like the original regexp did
+	Expect(t, PunctuationStripping.Normalize("たけ、やぶ"), "たけ、やぶ")
+}
+
+func TestMarkRemovalToReturnBaseCharacters(t *testing.T) {
+	Expect(t, MarkRemoval.Normalize("DÁBALE ÔNIBUS"), "DABALE ONIBUS")
+	Expect(t, MarkRemoval.Normalize("é"), "e")
+}
+
+func TestWidthFoldingToReturnCanonicalWidth(t *testing.T) {
+	Expect(t, WidthFolding.Normalize("Ｒａｃｅｃａｒ"), "Racecar")
+	Expect(t, WidthFolding.Normalize("ﾀｹﾔﾌﾞﾔｹﾀ"), "タケヤブヤケタ")
+}
+
+func TestTransliterationToReplaceRunes(t *testing.T) {
+	stage := Transliteration(map[rune]string{'æ': "ae", 'ø': "o", '-': ""})
+
+	Expect(t, stage.Normalize("sæl-ø"), "saelo")
+}
+
+func TestPipelineToRunStagesInOrder(t *testing.T) {
+	pipeline := Pipeline{
+		Transliteration(map[rune]string{'A': "b"}),
+		CaseFolding,
+	}
+
+	Expect(t, pipeline.Normalize("AB"), "bb")
+}
+
+func TestDefaultNormalizerToCleanPhrases(t *testing.T) {
+	tests := []struct {
+		phrase   string
+		expected string
+	}{
+		{"SOCORRAM-ME, SUBI NO ÔNIBUS EM MARROCOS", "socorrammesubinoonibusemmarrocos"},
+		{"DÁBALE ARROZ A LA ZORRA EL ABAD", "dabalearrozalazorraelabad"},
+		{"Was it a cat I saw?", "wasitacatisaw"},
+		{"e\u0301té", "ete"},
+		{"竹藪、焼けた", "竹藪、焼けた"},
+		{" \t.,!", ""},
+	}
+
+	for _, test := range tests {
+		Expect(t, DefaultNormalizer.Normalize(test.phrase), test.expected)
+	}
+}
+
+func TestValidateToUseCustomNormalizers(t *testing.T) {
+	palindrome := Palindrome{Phrase: "Ｒａｃｅ car", KeepCompatibility: true}
+	palindrome.Validate()
+	Expect(t, palindrome.Valid, false)
+
+	palindrome = Palindrome{Phrase: "Ｒａｃｅ car"}
+	palindrome.Validate(WidthFolding, CaseFolding, PunctuationStripping)
+	Expect(t, palindrome.Valid, true)
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+= Numeric palindromes
+A number is a palindrome in a given base when its digits in that base
+read the same backward as forward. 585 is a palindrome both in base 10
+and in base 2 (1001001001), while 10 is one in base 3 (101) but not in
+base 10.
+
+Numbers are handled with math/big, so there's no limit to their size.
+Bases go from 2 to 62, written with the digits 0-9, a-z and A-Z. The
+sign is not a digit and is ignored.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+)
+
+const (
+	MinBase = 2
+	MaxBase = big.MaxBase
+)
+
+// Validation of a number in a single base
+type BaseRepresentation struct {
+	Base   int    `json:"base"`
+	Digits string `json:"digits"`
+	Valid  bool   `json:"valid"`
+}
+
+type NumericPalindrome struct {
+	// Decimal integer, given either as a JSON number or a string
+	Number          json.Number          `json:"number"`
+	Bases           []int                `json:"bases"`
+	Representations []BaseRepresentation `json:"representations"`
+}
+
+/*
+Validates the number in each one of the bases.
+
+Base 10 is used when no bases are given.
+*/
+func ValidateNumber(n *big.Int, bases ...int) ([]BaseRepresentation, error) {
+	if n == nil {
+		return nil, errors.New("Invalid number")
+	}
+	if len(bases) == 0 {
+		bases = []int{10}
+	}
+
+	abs := new(big.Int).Abs(n)
+	representations := make([]BaseRepresentation, len(bases))
+	for i, base := range bases {
+		if base < MinBase || base > MaxBase {
+			return nil, errors.New("Invalid base")
+		}
+
+		digits := abs.Text(base)
+		representations[i] = BaseRepresentation{
+			Base:   base,
+			Digits: digits,
+			Valid:  isSymmetric(digits),
+		}
+	}
+
+	return representations, nil
+}
+
+/*
+Validates the number of the instance in each one of its bases.
+
+The number is read in base 10.
+*/
+func (p *NumericPalindrome) Validate() error {
+	n, ok := new(big.Int).SetString(p.Number.String(), 10)
+	if !ok {
+		return errors.New("Invalid number")
+	}
+
+	representations, err := ValidateNumber(n, p.Bases...)
+	if err != nil {
+		return err
+	}
+
+	p.Representations = representations
+	return nil
+}
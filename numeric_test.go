@@ -0,0 +1,48 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestValidateNumberToCheckEachBase(t *testing.T) {
+	representations, err := ValidateNumber(big.NewInt(585), 10, 2, 16)
+
+	Expect(t, err, nil)
+	Expect(t, len(representations), 3)
+	Expect(t, representations[0], BaseRepresentation{10, "585", true})
+	Expect(t, representations[1], BaseRepresentation{2, "1001001001", true})
+	Expect(t, representations[2], BaseRepresentation{16, "249", false})
+}
+
+func TestValidateNumberToDefaultToBase10(t *testing.T) {
+	representations, _ := ValidateNumber(big.NewInt(-121))
+
+	Expect(t, len(representations), 1)
+	Expect(t, representations[0], BaseRepresentation{10, "121", true})
+}
+
+func TestValidateNumberToFailOnInvalidBase(t *testing.T) {
+	_, err := ValidateNumber(big.NewInt(585), 1)
+	ExpectNotNil(t, err)
+
+	_, err = ValidateNumber(big.NewInt(585), 63)
+	ExpectNotNil(t, err)
+}
+
+func TestNumericPalindromeToValidateBigIntegers(t *testing.T) {
+	palindrome := NumericPalindrome{
+		Number: "987654321012345678901234575432109876543210123456789",
+		Bases:  []int{10, 36},
+	}
+
+	Expect(t, palindrome.Validate(), nil)
+	Expect(t, palindrome.Representations[0].Valid, true)
+	Expect(t, palindrome.Representations[1].Valid, false)
+}
+
+func TestNumericPalindromeToFailOnInvalidNumber(t *testing.T) {
+	palindrome := NumericPalindrome{Number: "12.5"}
+
+	ExpectNotNil(t, palindrome.Validate())
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+= Offset mapping
+Normalization drops, expands and merges runes, so a position in the
+normalized phrase says nothing about where it is in the original one.
+To keep track of it, the phrase is split in segments which are
+normalized one by one:
+
+	"Á, b" => [Á] [,] [ ] [b] => [a] [] [] [b]
+
+Each normalized rune then points back to the bytes of the segment it
+came from.
+
+A segment is a base rune along with the combining marks that follow
+it, halfwidth voicing marks included, which are the boundaries the
+Unicode normalization forms never cross. On Japanese phrases, each
+word found in the reading dictionary is a segment by itself, and the
+long vowel mark is kept with the kana it extends. All the stock stages
+give the same result when applied segment by segment or to the whole
+phrase at once.
+
+Normalizing a long phrase segment by segment takes a few times longer
+than all at once, though. So phrases compared by characters are
+normalized in chunks of a few kilobytes instead, which are made of
+whole segments too. Only the chunks holding the runes whose position is
+needed are normalized again, segment by segment, to find it.
+*/
+
+package main
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	// Third party packages
+	"golang.org/x/text/unicode/norm"
+)
+
+// Bytes of the phrase normalized at once by normalizeChunks
+const normalizedChunkSize = 4 << 10
+
+// Position in the original phrase
+type Offset struct {
+	Byte int `json:"byte"`
+	Rune int `json:"rune"`
+}
+
+// Normalized text and the bytes of the phrase it was produced from
+type span struct {
+	text       string
+	start, end int
+}
+
+/*
+Splits the phrase in segments and normalizes each one of them.
+
+Segments normalized into an empty string are left out.
+*/
+func (e *Engine) spans(phrase string) ([]span, error) {
+	normalizer, err := e.normalizer()
+	if err != nil {
+		return nil, err
+	}
+
+	var converter *ReadingConverter
+	if isJapanese(phrase) {
+		converter = e.converter()
+	}
+
+	return e.segmentSpans(phrase, 0, len(phrase), normalizer, converter), nil
+}
+
+/*
+Normalizes the segments of phrase[start:end] one by one, as spans does.
+*/
+func (e *Engine) segmentSpans(phrase string, start, end int, normalizer Normalizer, converter *ReadingConverter) []span {
+	// ASCII segments skip the stages when they'd be left as they are
+	ascii := e.readsASCIIAsIs()
+
+	var spans []span
+	for i := start; i < end; {
+		text, size := nextSegment(phrase[i:end], converter, true)
+		if ascii && isASCII(text) {
+			text, _ = normalizeASCII(text)
+		} else {
+			text = normalizer.Normalize(text)
+		}
+		if text != "" {
+			spans = append(spans, span{text, i, i + size})
+		}
+		i += size
+	}
+
+	return spans
+}
+
+/*
+Normalizes phrase[start:end] in chunks of about normalizedChunkSize
+bytes ending at segment boundaries, keeping the bytes each chunk came
+from.
+*/
+func (e *Engine) normalizeChunks(phrase string, start, end int, normalizer Normalizer) []span {
+	// ASCII chunks skip the stages when they'd be left as they are
+	ascii := e.readsASCIIAsIs()
+
+	var chunks []span
+	for i := start; i < end; {
+		j := end
+		if j-i > normalizedChunkSize {
+			if j = segmentStart(phrase, i+normalizedChunkSize, i); j == i {
+				j = end
+			}
+		}
+
+		text, ok := "", false
+		if ascii {
+			text, ok = normalizeASCII(phrase[i:j])
+		}
+		if !ok {
+			text = normalizer.Normalize(phrase[i:j])
+		}
+		chunks = append(chunks, span{text, i, j})
+		i = j
+	}
+
+	return chunks
+}
+
+/*
+Returns the normalized text of the chunks, joined.
+*/
+func joinChunks(chunks []span) string {
+	size := 0
+	for _, chunk := range chunks {
+		size += len(chunk.text)
+	}
+
+	var b strings.Builder
+	b.Grow(size)
+	for _, chunk := range chunks {
+		b.WriteString(chunk.text)
+	}
+
+	return b.String()
+}
+
+/*
+Returns the segment at the beginning of s, converted to its reading
+when a converter is given, and its size in bytes.
+
+Unless atEOF is set, s may be followed by more text. If it's not long
+enough to be sure where the segment ends, the size returned is -1.
+*/
+func nextSegment(s string, converter *ReadingConverter, atEOF bool) (string, int) {
+	if converter != nil {
+		if word, reading := converter.match(s); word != "" {
+			return reading, len(word)
+		}
+	}
+
+	size := segmentSize(s, atEOF)
+	if size < 0 {
+		return "", -1
+	}
+
+	return s[:size], size
+}
+
+/*
+Returns the size in bytes of the segment at the beginning of s, or -1
+if more text is needed to tell.
+*/
+func segmentSize(s string, atEOF bool) int {
+	size := norm.NFKC.NextBoundaryInString(s, atEOF)
+	if size < 0 {
+		return -1
+	}
+
+	// The long vowel mark is read along with the kana before it
+	for {
+		if size == len(s) {
+			if atEOF {
+				return size
+			}
+			return -1
+		}
+
+		r, n := utf8.DecodeRuneInString(s[size:])
+		if r != longVowelMark {
+			return size
+		}
+		size += n
+	}
+}
+
+/*
+Returns the offset of a byte position of the phrase.
+*/
+func offsetOf(phrase string, pos int) Offset {
+	return Offset{Byte: pos, Rune: utf8.RuneCountInString(phrase[:pos])}
+}
+
+/*
+Returns the offsets of the start and end of each unit. The runes before
+the units are counted once, as long as they're in the order of the
+phrase.
+*/
+func unitOffsets(phrase string, units []span) (starts, ends []Offset) {
+	starts, ends = make([]Offset, len(units)), make([]Offset, len(units))
+	pos, runes := 0, 0
+	for i, unit := range units {
+		if unit.start < pos {
+			pos, runes = 0, 0
+		}
+		runes += utf8.RuneCountInString(phrase[pos:unit.start])
+		pos = unit.start
+
+		starts[i] = Offset{Byte: unit.start, Rune: runes}
+		ends[i] = Offset{
+			Byte: unit.end,
+			Rune: runes + utf8.RuneCountInString(phrase[unit.start:unit.end]),
+		}
+	}
+
+	return starts, ends
+}
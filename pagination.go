@@ -0,0 +1,232 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+= Pagination
+Listing a collection of a few hundred thousand palindromes at once
+takes more memory than the server should spend on a single request.
+The list is split in pages of up to MaxPageSize palindromes instead,
+in the order of the sort of the list (see filters.go), and then of
+their ID.
+
+A page is found from a Cursor, the palindrome it starts after or, going
+backward, the one it ends before, identified by its ID and the value of
+its sort field. Clients get it as an opaque string in the links to the
+next and previous pages, so the way it's encoded can change without
+breaking them. Unlike an offset, the cursor points at the same place of
+the list while palindromes are added and removed.
+*/
+
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+
+	// Third party packages
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	DefaultPageSize = 100
+	MaxPageSize     = 1000
+)
+
+// Position of the list a page starts from, the first one when empty
+type Cursor struct {
+	// Last ID before the page, or first ID after it going backward
+	ID bson.ObjectId `bson:"i"`
+	// Value of the sort field of the palindrome of the ID
+	Value interface{} `bson:"v"`
+	// Sort of the list the cursor belongs to, and its order
+	Sort       string `bson:"s"`
+	Descending bool   `bson:"d,omitempty"`
+	Backward   bool   `bson:"b,omitempty"`
+}
+
+// A page of palindromes, with the links to the pages around it
+type Page struct {
+	Palindromes []Palindrome `json:"palindromes"`
+	// Missing on the last and first pages
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+	// Palindromes in the whole list, when requested
+	Total *int `json:"total,omitempty"`
+}
+
+/*
+Encodes the cursor as an opaque string.
+*/
+func (c Cursor) String() string {
+	b, err := bson.Marshal(c)
+	if err != nil {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+/*
+Decodes a cursor encoded by Cursor.String. The empty string is the
+cursor of the first page.
+*/
+func ParseCursor(s string) (Cursor, error) {
+	var cursor Cursor
+	if s == "" {
+		return cursor, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || bson.Unmarshal(b, &cursor) != nil || !cursor.ID.Valid() {
+		return Cursor{}, errors.New("Invalid cursor")
+	}
+
+	// The value ends up in the query, so it can't be anything but a
+	// value of the sort field
+	if !cursor.valueFitsSort() {
+		return Cursor{}, errors.New("Invalid cursor")
+	}
+
+	return cursor, nil
+}
+
+/*
+Reports whether the value of the cursor is of the type of the field of
+its sort: none for created, as the ID is the field.
+*/
+func (c Cursor) valueFitsSort() bool {
+	switch c.Sort {
+	case "created":
+		return c.Value == nil
+	case "length":
+		_, ok := c.Value.(int)
+		return ok
+	case "phrase":
+		_, ok := c.Value.(string)
+		return ok
+	}
+
+	return false
+}
+
+/*
+Returns up to limit palindromes of the list from the cursor on.
+Reports whether there are more of them past the page, in the direction
+of the cursor.
+*/
+func (dao *Dao) FindPage(list ListQuery, cursor Cursor, limit int) ([]Palindrome, bool, error) {
+	field := sortFields[list.Sort]
+
+	// Pages ending before the cursor are read in reverse order, and
+	// reversed back afterwards
+	operator, order := "$gt", ""
+	if list.Descending != cursor.Backward {
+		operator, order = "$lt", "-"
+	}
+
+	query := list.Filter
+	if cursor.ID != "" {
+		query = bson.M{"$and": []bson.M{list.Filter, cursor.past(field, operator)}}
+	}
+
+	sort := []string{order + "_id"}
+	if field != "_id" {
+		sort = []string{order + field, order + "_id"}
+	}
+
+	palindromes := []Palindrome{}
+	err := dao.Database().C("palindromes").Find(query).Sort(sort...).Limit(limit + 1).All(&palindromes)
+	if err != nil {
+		return nil, false, err
+	}
+
+	more := len(palindromes) > limit
+	if more {
+		palindromes = palindromes[:limit]
+	}
+	if cursor.Backward {
+		for i, j := 0, len(palindromes)-1; i < j; i, j = i+1, j-1 {
+			palindromes[i], palindromes[j] = palindromes[j], palindromes[i]
+		}
+	}
+
+	return palindromes, more, nil
+}
+
+/*
+Returns the condition matching the palindromes past the cursor, by the
+operator, in a list sorted by the field and then by ID.
+*/
+func (c Cursor) past(field, operator string) bson.M {
+	if field == "_id" {
+		return bson.M{"_id": bson.M{operator: c.ID}}
+	}
+
+	return bson.M{"$or": []bson.M{
+		{field: bson.M{operator: c.Value}},
+		{field: c.Value, "_id": bson.M{operator: c.ID}},
+	}}
+}
+
+/*
+Returns the cursors of the pages after and before the palindromes found
+from the cursor, nil when there's none.
+*/
+func pageCursors(list ListQuery, cursor Cursor, palindromes []Palindrome, more bool) (next, prev *Cursor) {
+	if len(palindromes) == 0 {
+		return nil, nil
+	}
+
+	first, last := palindromes[0], palindromes[len(palindromes)-1]
+	// A page reached from a cursor has palindromes on the other side
+	if more || cursor.Backward {
+		next = &Cursor{ID: last.ID, Value: sortValue(last, list.Sort), Sort: list.Sort, Descending: list.Descending}
+	}
+	if more && cursor.Backward || !cursor.Backward && cursor.ID != "" {
+		prev = &Cursor{ID: first.ID, Value: sortValue(first, list.Sort), Sort: list.Sort, Descending: list.Descending, Backward: true}
+	}
+
+	return next, prev
+}
+
+/*
+Reads the limit of the request, DefaultPageSize when missing.
+*/
+func pageLimit(r *http.Request) (int, error) {
+	value := r.URL.Query().Get("limit")
+	if value == "" {
+		return DefaultPageSize, nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > MaxPageSize {
+		return 0, errors.New("Invalid limit")
+	}
+
+	return limit, nil
+}
+
+/*
+Returns the link to the page of the cursor, keeping the other
+parameters of the request.
+*/
+func pageLink(r *http.Request, cursor *Cursor, limit int) string {
+	query := r.URL.Query()
+	query.Set("cursor", cursor.String())
+	query.Set("limit", strconv.Itoa(limit))
+
+	return r.URL.Path + "?" + query.Encode()
+}
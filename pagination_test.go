@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"testing"
+
+	// Third party packages
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestParseCursorToDecodeCursorString(t *testing.T) {
+	cursors := []Cursor{
+		{ID: bson.NewObjectId(), Sort: "created"},
+		{ID: bson.NewObjectId(), Sort: "created", Backward: true},
+		{ID: bson.NewObjectId(), Value: 0, Sort: "length"},
+		{ID: bson.NewObjectId(), Value: "racecar", Sort: "phrase", Backward: true},
+		{ID: bson.NewObjectId(), Value: 7, Sort: "length", Descending: true},
+	}
+
+	for _, cursor := range cursors {
+		parsed, err := ParseCursor(cursor.String())
+		Expect(t, err, nil)
+		Expect(t, parsed, cursor)
+	}
+
+	parsed, err := ParseCursor("")
+	Expect(t, err, nil)
+	Expect(t, parsed, Cursor{})
+}
+
+func TestParseCursorToFailOnInvalidCursor(t *testing.T) {
+	// Well formed, but without an ID
+	b, _ := bson.Marshal(bson.M{"s": "created"})
+	noID := base64.RawURLEncoding.EncodeToString(b)
+
+	// Values that aren't of the type of the sort field
+	var mistyped []string
+	for _, cursor := range []Cursor{
+		{ID: bson.NewObjectId(), Value: "racecar", Sort: "created"},
+		{ID: bson.NewObjectId(), Value: bson.M{"$ne": nil}, Sort: "length"},
+		{ID: bson.NewObjectId(), Value: 7, Sort: "phrase"},
+		{ID: bson.NewObjectId(), Sort: "valid"},
+	} {
+		mistyped = append(mistyped, cursor.String())
+	}
+
+	for _, s := range append([]string{"invalid", "!!", noID}, mistyped...) {
+		_, err := ParseCursor(s)
+		ExpectNotNil(t, err)
+	}
+}
+
+func TestCursorPastToCompareSortFieldThenID(t *testing.T) {
+	id := bson.NewObjectId()
+
+	past := Cursor{ID: id, Sort: "created"}.past("_id", "$gt")
+	Expect(t, fmt.Sprint(past), fmt.Sprint(bson.M{"_id": bson.M{"$gt": id}}))
+
+	past = Cursor{ID: id, Value: 7, Sort: "length"}.past("length", "$lt")
+	Expect(t, fmt.Sprint(past), fmt.Sprint(bson.M{"$or": []bson.M{
+		{"length": bson.M{"$lt": 7}},
+		{"length": 7, "_id": bson.M{"$lt": id}},
+	}}))
+}
+
+func TestPageCursorsToLinkPagesAround(t *testing.T) {
+	first, last := bson.NewObjectId(), bson.NewObjectId()
+	palindromes := []Palindrome{{ID: first}, {ID: last}}
+	list := ListQuery{Sort: "created"}
+	from := Cursor{ID: bson.NewObjectId(), Sort: "created"}
+
+	tests := []struct {
+		cursor Cursor
+		more   bool
+		next   *Cursor
+		prev   *Cursor
+	}{
+		// First page
+		{Cursor{}, true, &Cursor{ID: last, Sort: "created"}, nil},
+		{Cursor{}, false, nil, nil},
+		// Going forward
+		{from, true, &Cursor{ID: last, Sort: "created"}, &Cursor{ID: first, Sort: "created", Backward: true}},
+		{from, false, nil, &Cursor{ID: first, Sort: "created", Backward: true}},
+		// Going backward
+		{Cursor{ID: from.ID, Sort: "created", Backward: true}, true, &Cursor{ID: last, Sort: "created"}, &Cursor{ID: first, Sort: "created", Backward: true}},
+		{Cursor{ID: from.ID, Sort: "created", Backward: true}, false, &Cursor{ID: last, Sort: "created"}, nil},
+	}
+
+	for _, test := range tests {
+		next, prev := pageCursors(list, test.cursor, palindromes, test.more)
+		expectCursor(t, next, test.next)
+		expectCursor(t, prev, test.prev)
+	}
+}
+
+func expectCursor(t *testing.T, actual, expected *Cursor) {
+	Expect(t, actual == nil, expected == nil)
+	if actual != nil && expected != nil {
+		Expect(t, *actual, *expected)
+	}
+}
+
+func TestPageCursorsToKeepSortValue(t *testing.T) {
+	palindromes := []Palindrome{
+		{ID: bson.NewObjectId(), Phrase: "racecar", Length: 7},
+		{ID: bson.NewObjectId(), Phrase: "kayak", Length: 5},
+	}
+	list := ListQuery{Sort: "length", Descending: true}
+	from := Cursor{ID: bson.NewObjectId(), Value: 9, Sort: "length", Descending: true}
+
+	next, prev := pageCursors(list, from, palindromes, true)
+	expectCursor(t, next, &Cursor{ID: palindromes[1].ID, Value: 5, Sort: "length", Descending: true})
+	expectCursor(t, prev, &Cursor{ID: palindromes[0].ID, Value: 7, Sort: "length", Descending: true, Backward: true})
+}
+
+func TestPageLimitToReadLimit(t *testing.T) {
+	tests := []struct {
+		url   string
+		limit int
+		valid bool
+	}{
+		{"/palindrome", DefaultPageSize, true},
+		{"/palindrome?limit=5", 5, true},
+		{"/palindrome?limit=0", 0, false},
+		{"/palindrome?limit=1001", 0, false},
+		{"/palindrome?limit=five", 0, false},
+	}
+
+	for _, test := range tests {
+		r, _ := http.NewRequest("GET", test.url, nil)
+		limit, err := pageLimit(r)
+		Expect(t, limit, test.limit)
+		Expect(t, err == nil, test.valid)
+	}
+}
+
+func TestPageLinkToKeepParameters(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/palindrome?total=true&cursor=old", nil)
+	cursor := &Cursor{ID: bson.ObjectIdHex("58eedfb5b7fc13821176df2c")}
+
+	Expect(t, pageLink(r, cursor, 10), "/palindrome?cursor="+cursor.String()+"&limit=10&total=true")
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+= Parallel validation
+A phrase of a few megabytes keeps a single goroutine busy for a while,
+mostly normalizing it. With Engine.Workers set above 1, phrases of at
+least ParallelMinBytes are validated by that many goroutines instead.
+The handlers validating palindromes are given the number of workers of
+the settings of the server, one per core by default, which they set in
+Palindrome.Workers.
+
+The phrase is split at segment boundaries (see offsets.go) in pieces
+that are normalized concurrently. The normalized text is then split in
+pairs of mirrored chunks, the first chunk of the first half with the
+last one of the second half and so on, which are compared concurrently
+too. The first mismatch found cancels the chunks still waiting, and so
+does the context given to IsPalindromeContext.
+
+ASCII phrases are still read by the fast path (see symmetry.go), which
+is quicker than any number of workers. Only the character unit is
+compared in parallel; Japanese phrases and custom normalizers are still
+normalized by a single goroutine.
+*/
+
+package main
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// Phrases shorter than that are validated by a single goroutine
+	ParallelMinBytes = 1 << 20
+
+	// Bytes of the phrase normalized by a worker at a time
+	parallelPieceSize = 256 << 10
+	// Runes compared by a worker at a time, from each end
+	parallelChunkSize = 64 << 10
+)
+
+/*
+Reports whether the phrase is a palindrome as IsPalindrome does,
+stopping as soon as ctx is done, in which case its error is returned.
+*/
+func (e *Engine) IsPalindromeContext(ctx context.Context, phrase string) (bool, string, error) {
+	if err := ctx.Err(); err != nil {
+		return false, "", err
+	}
+
+	if e.comparesInParallel(phrase) {
+		return e.isPalindromeParallel(ctx, phrase)
+	}
+
+	return e.IsPalindrome(phrase)
+}
+
+func (e *Engine) comparesInParallel(phrase string) bool {
+	characters := (e.Unit == "" || e.Unit == UnitCharacter) && e.Chinese != ChinesePinyin
+	return e.Workers > 1 && len(phrase) >= ParallelMinBytes && characters
+}
+
+func (e *Engine) isPalindromeParallel(ctx context.Context, phrase string) (bool, string, error) {
+	if e.readsASCIIAsIs() {
+		if valid, ok := isASCIIPalindrome(phrase); ok {
+			return valid, "", nil
+		}
+	}
+
+	var runes []rune
+	var reading string
+	if e.Normalizer == nil && !isJapanese(phrase) {
+		chunks, err := e.normalizeParallel(ctx, phrase, nil)
+		if err != nil {
+			return false, "", err
+		}
+		runes = []rune(joinChunks(chunks))
+	} else {
+		word, r, err := e.Normalize(phrase)
+		if err != nil {
+			return false, "", err
+		}
+		runes, reading = []rune(word), r
+	}
+
+	valid, err := isSymmetricParallel(ctx, runes, e.Workers)
+	return valid, reading, err
+}
+
+/*
+Normalizes the phrase in pieces ending at segment boundaries, with the
+workers of the engine. Each piece is normalized in chunks, as
+normalizeChunks does, and the chunks of every piece are returned in
+order. Unless folds is nil, the letter folds applied are added to it,
+in the order their letters first appear: each piece records its own, as
+the stage recording them can't be shared.
+*/
+func (e *Engine) normalizeParallel(ctx context.Context, phrase string, folds *[]Fold) ([]span, error) {
+	var bounds []int
+	for start := 0; start < len(phrase); {
+		end := len(phrase)
+		if end-start > parallelPieceSize {
+			if end = segmentStart(phrase, start+parallelPieceSize, start); end == start {
+				end = len(phrase)
+			}
+		}
+		bounds = append(bounds, start)
+		start = end
+	}
+	bounds = append(bounds, len(phrase))
+
+	pieces := make([][]span, len(bounds)-1)
+	normalizers := make([]Normalizer, len(pieces))
+	pieceFolds := make([][]Fold, len(pieces))
+	for k := range normalizers {
+		var err error
+		if folds != nil {
+			normalizers[k], err = e.foldingNormalizer(&pieceFolds[k])
+		} else if k == 0 {
+			normalizers[k], err = e.normalizer()
+		} else {
+			normalizers[k] = normalizers[0]
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err := runParallel(ctx, e.Workers, len(pieces), func(k int) bool {
+		cache := e.segmentCache(normalizers[k])
+		pieces[k] = e.normalizeChunks(phrase, bounds[k], bounds[k+1], cache)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if folds != nil {
+		seen := make(map[string]bool)
+		for _, applied := range pieceFolds {
+			for _, fold := range applied {
+				if !seen[fold.Letter] {
+					seen[fold.Letter] = true
+					*folds = append(*folds, fold)
+				}
+			}
+		}
+	}
+
+	var chunks []span
+	for _, piece := range pieces {
+		chunks = append(chunks, piece...)
+	}
+
+	return chunks, nil
+}
+
+/*
+Compares the mirrored chunks of runes with the given number of workers.
+*/
+func isSymmetricParallel(ctx context.Context, runes []rune, workers int) (bool, error) {
+	n := len(runes)
+	half := n / 2
+	chunks := (half + parallelChunkSize - 1) / parallelChunkSize
+
+	return runParallel(ctx, workers, chunks, func(k int) bool {
+		end := (k + 1) * parallelChunkSize
+		if end > half {
+			end = half
+		}
+
+		for i := k * parallelChunkSize; i < end; i++ {
+			if runes[i] != runes[n-1-i] {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+/*
+Runs task for every index up to n with the given number of workers, at
+least one, until a task returns false or ctx is done. Reports whether
+every task returned true.
+*/
+func runParallel(ctx context.Context, workers, n int, task func(k int) bool) (bool, error) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	running, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var failed int32
+	var wg sync.WaitGroup
+	tasks := make(chan int)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range tasks {
+				if !task(k) {
+					atomic.StoreInt32(&failed, 1)
+					cancel()
+				}
+			}
+		}()
+	}
+
+feed:
+	for k := 0; k < n; k++ {
+		select {
+		case tasks <- k:
+		case <-running.Done():
+			break feed
+		}
+	}
+	close(tasks)
+	wg.Wait()
+
+	if atomic.LoadInt32(&failed) == 1 {
+		return false, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+var parallelHalf = strings.Repeat("Dábale arroz a la zorra él abad, Ünï ", 1<<14)
+
+func TestIsPalindromeContextToMatchSequential(t *testing.T) {
+	phrases := []string{
+		parallelHalf + "Ω" + reverse(parallelHalf),
+		parallelHalf + "Ω" + reverse(parallelHalf) + "x",
+		parallelHalf + "Ωx" + reverse(parallelHalf),
+	}
+
+	sequential := new(Engine)
+	parallel := &Engine{Workers: 4}
+	for _, phrase := range phrases {
+		expected, _, _ := sequential.IsPalindrome(phrase)
+		valid, _, err := parallel.IsPalindromeContext(context.Background(), phrase)
+		Expect(t, err, nil)
+		Expect(t, valid, expected)
+	}
+}
+
+func TestEngineValidateToMatchSequentialInParallel(t *testing.T) {
+	phrases := []string{
+		parallelHalf + "Ωæ" + reverse(parallelHalf),
+		parallelHalf + "ab" + reverse(parallelHalf),
+		"Ô" + parallelHalf + "x" + reverse(parallelHalf) + "á",
+		parallelHalf + strings.Repeat("ab", MaxMismatches) + parallelHalf,
+	}
+
+	sequential := new(Engine)
+	parallel := &Engine{Workers: 4}
+	for _, phrase := range phrases {
+		expected, _ := sequential.Validate(phrase)
+		result, err := parallel.Validate(phrase)
+		Expect(t, err, nil)
+		Expect(t, result.Valid, expected.Valid)
+		Expect(t, result.Normalized, expected.Normalized)
+		Expect(t, fmt.Sprint(result.Folds), fmt.Sprint(expected.Folds))
+		Expect(t, result.MismatchesTruncated, expected.MismatchesTruncated)
+		Expect(t, fmt.Sprint(result.Mismatches), fmt.Sprint(expected.Mismatches))
+	}
+}
+
+func TestIsPalindromeContextToStopOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	engine := &Engine{Workers: 4}
+	_, _, err := engine.IsPalindromeContext(ctx, parallelHalf+reverse(parallelHalf))
+	Expect(t, err, context.Canceled)
+}
+
+func TestIsSymmetricParallelToCompareMirroredChunks(t *testing.T) {
+	runes := []rune(strings.Repeat("ab", 3*parallelChunkSize) + strings.Repeat("ba", 3*parallelChunkSize))
+
+	valid, err := isSymmetricParallel(context.Background(), runes, 3)
+	Expect(t, err, nil)
+	Expect(t, valid, true)
+
+	runes[len(runes)/2-1] = 'x'
+	valid, _ = isSymmetricParallel(context.Background(), runes, 3)
+	Expect(t, valid, false)
+}
+
+func TestRunParallelToStopOnFailure(t *testing.T) {
+	var ran int32
+	valid, err := runParallel(context.Background(), 2, 1000, func(k int) bool {
+		atomic.AddInt32(&ran, 1)
+		return false
+	})
+
+	Expect(t, err, nil)
+	Expect(t, valid, false)
+	Expect(t, atomic.LoadInt32(&ran) < 1000, true)
+}
+
+func BenchmarkParallelValidator(b *testing.B) {
+	for _, size := range []int{1, 4, 16} {
+		half := strings.Repeat("Dábale arroz a la zorra él abad ", size<<14)
+		phrase := half + reverse(half)
+
+		for _, workers := range []int{1, 2, 4, 8} {
+			engine := &Engine{Workers: workers}
+			b.Run(fmt.Sprintf("%dmb/workers-%d", size, workers), func(b *testing.B) {
+				b.SetBytes(int64(len(phrase)))
+				for i := 0; i < b.N; i++ {
+					engine.IsPalindrome(phrase)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkParallelComparison(b *testing.B) {
+	half := []rune(strings.Repeat("abcdefgh", 1<<20))
+	runes := append(half, half...)
+	for i := range half {
+		runes[len(runes)-1-i] = half[i]
+	}
+
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			isSymmetricRunes(runes)
+		}
+	})
+	for _, workers := range []int{2, 4, 8} {
+		b.Run(fmt.Sprintf("workers-%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				isSymmetricParallel(context.Background(), runes, workers)
+			}
+		})
+	}
+}
+
+func isSymmetricRunes(runes []rune) bool {
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		if runes[i] != runes[j] {
+			return false
+		}
+	}
+	return true
+}
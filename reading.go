@@ -0,0 +1,127 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Japanese palindromes are evaluated on their phonetic form, so kanji
+must be replaced by their reading in hiragana before the phrase is
+normalized:
+
+	竹藪焼けた => たけやぶやけた
+
+The ReadingConverter does it offline, using a dictionary compiled into
+the binary (see reading_dict.go). The phrase is segmented with a
+longest-match strategy: at each position the longest dictionary entry
+that matches the text is replaced by its reading. Runes that aren't
+part of any entry are kept as they are.
+
+Compound words are read differently from their isolated characters
+(何 is なに but 何匹 is なんびき), which is why entries are not limited
+to a single kanji and why the longest entry always wins.
+*/
+
+package main
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type ReadingConverter struct {
+	dict map[string]string
+	// length, in runes, of the longest entry in dict
+	maxLen int
+}
+
+// The converter used by Validate, loaded with the embedded dictionary.
+var DefaultReadingConverter = NewReadingConverter(japaneseReadings)
+
+func NewReadingConverter(dict map[string]string) *ReadingConverter {
+	converter := &ReadingConverter{dict: dict}
+	for word := range dict {
+		if n := utf8.RuneCountInString(word); n > converter.maxLen {
+			converter.maxLen = n
+		}
+	}
+
+	return converter
+}
+
+/*
+Converts every dictionary entry found in s to its reading.
+*/
+func (c *ReadingConverter) Convert(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for len(s) > 0 {
+		word, reading := c.match(s)
+		if word == "" {
+			_, size := utf8.DecodeRuneInString(s)
+			b.WriteString(s[:size])
+			s = s[size:]
+			continue
+		}
+
+		b.WriteString(reading)
+		s = s[len(word):]
+	}
+
+	return b.String()
+}
+
+/*
+Looks up the longest entry at the beginning of s.
+
+Returns the matched text and its reading, or empty strings if
+no entry matches.
+*/
+func (c *ReadingConverter) match(s string) (string, string) {
+	// Byte offsets of the first maxLen runes
+	ends := make([]int, 0, c.maxLen)
+	for i := range s {
+		if i > 0 {
+			ends = append(ends, i)
+		}
+		if len(ends) == c.maxLen {
+			break
+		}
+	}
+	if len(ends) < c.maxLen {
+		ends = append(ends, len(s))
+	}
+
+	for i := len(ends) - 1; i >= 0; i-- {
+		if reading, ok := c.dict[s[:ends[i]]]; ok {
+			return s[:ends[i]], reading
+		}
+	}
+
+	return "", ""
+}
+
+/*
+Reports whether the text should be read as Japanese.
+
+Kanji alone aren't enough, as they are shared with Chinese. The phrase
+is taken as Japanese only when it also has hiragana or katakana.
+*/
+func isJapanese(s string) bool {
+	for _, r := range s {
+		if unicode.In(r, unicode.Hiragana, unicode.Katakana) {
+			return true
+		}
+	}
+	return false
+}
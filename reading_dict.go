@@ -0,0 +1,264 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Embedded kanji reading dictionary.
+
+Entries map a surface form to its reading in hiragana. Verbs and
+adjectives are listed with their okurigana whenever the reading of the
+stem alone would be ambiguous (焼け => やけ, 負け => まけ). The list is
+not meant to be a complete dictionary of the language, but it covers
+the vocabulary of the well known palindromes and the most common
+characters used in them.
+*/
+
+package main
+
+var japaneseReadings = map[string]string{
+	// Compounds
+	"竹藪":   "たけやぶ",
+	"新聞紙":  "しんぶんし",
+	"世の中":  "よのなか",
+	"鳴門":   "なると",
+	"鳴門巻":  "なるとまき",
+	"烏賊":   "いか",
+	"留守":   "るす",
+	"機敏":   "きびん",
+	"子猫":   "こねこ",
+	"仔猫":   "こねこ",
+	"何匹":   "なんびき",
+	"日本":   "にほん",
+	"今日":   "きょう",
+	"明日":   "あした",
+	"昨日":   "きのう",
+	"一期一会": "いちごいちえ",
+	"一緒":   "いっしょ",
+	"時計":   "とけい",
+	"野菜":   "やさい",
+	"果物":   "くだもの",
+	"手紙":   "てがみ",
+	"眼鏡":   "めがね",
+	"大人":   "おとな",
+	"子供":   "こども",
+	"田舎":   "いなか",
+	"相撲":   "すもう",
+	"夕焼け":  "ゆうやけ",
+	"確か":   "たしか",
+	"軽い":   "かるい",
+	"白い":   "しろい",
+	"赤い":   "あかい",
+	"青い":   "あおい",
+	"黒い":   "くろい",
+	"長い":   "ながい",
+	"短い":   "みじかい",
+	"高い":   "たかい",
+	"安い":   "やすい",
+	"古い":   "ふるい",
+	"新しい":  "あたらしい",
+	"美しい":  "うつくしい",
+	"焼け":   "やけ",
+	"焼き":   "やき",
+	"焼く":   "やく",
+	"負け":   "まけ",
+	"負く":   "まく",
+	"貸し":   "かし",
+	"貸す":   "かす",
+	"取る":   "とる",
+	"取り":   "とり",
+	"見る":   "みる",
+	"見た":   "みた",
+	"来る":   "くる",
+	"来た":   "きた",
+	"行く":   "いく",
+	"書く":   "かく",
+	"聞く":   "きく",
+	"読む":   "よむ",
+	"住む":   "すむ",
+	"泣く":   "なく",
+	"鳴く":   "なく",
+	"食べ":   "たべ",
+	"寝る":   "ねる",
+	"居る":   "いる",
+	"煮る":   "にる",
+	"似る":   "にる",
+	"切る":   "きる",
+	"着る":   "きる",
+	"止め":   "やめ",
+	"任せ":   "まかせ",
+
+	// Single characters, with their most common reading
+	"私": "わたし",
+	"僕": "ぼく",
+	"君": "きみ",
+	"彼": "かれ",
+	"人": "ひと",
+	"子": "こ",
+	"母": "はは",
+	"父": "ちち",
+	"竹": "たけ",
+	"藪": "やぶ",
+	"木": "き",
+	"森": "もり",
+	"林": "はやし",
+	"山": "やま",
+	"川": "かわ",
+	"海": "うみ",
+	"空": "そら",
+	"雨": "あめ",
+	"雪": "ゆき",
+	"花": "はな",
+	"草": "くさ",
+	"葉": "は",
+	"石": "いし",
+	"岩": "いわ",
+	"田": "た",
+	"畑": "はたけ",
+	"村": "むら",
+	"町": "まち",
+	"国": "くに",
+	"島": "しま",
+	"道": "みち",
+	"家": "いえ",
+	"門": "もん",
+	"窓": "まど",
+	"庭": "にわ",
+	"池": "いけ",
+	"橋": "はし",
+	"箸": "はし",
+	"端": "はし",
+	"皿": "さら",
+	"酒": "さけ",
+	"水": "みず",
+	"湯": "ゆ",
+	"火": "ひ",
+	"日": "ひ",
+	"月": "つき",
+	"星": "ほし",
+	"夜": "よる",
+	"朝": "あさ",
+	"昼": "ひる",
+	"晩": "ばん",
+	"今": "いま",
+	"春": "はる",
+	"夏": "なつ",
+	"秋": "あき",
+	"冬": "ふゆ",
+	"猫": "ねこ",
+	"犬": "いぬ",
+	"馬": "うま",
+	"牛": "うし",
+	"鳥": "とり",
+	"魚": "さかな",
+	"虫": "むし",
+	"蟹": "かに",
+	"亀": "かめ",
+	"鹿": "しか",
+	"熊": "くま",
+	"狸": "たぬき",
+	"狐": "きつね",
+	"蛸": "たこ",
+	"目": "め",
+	"耳": "みみ",
+	"口": "くち",
+	"手": "て",
+	"足": "あし",
+	"首": "くび",
+	"顔": "かお",
+	"頭": "あたま",
+	"心": "こころ",
+	"声": "こえ",
+	"名": "な",
+	"字": "じ",
+	"本": "ほん",
+	"紙": "かみ",
+	"神": "かみ",
+	"髪": "かみ",
+	"上": "うえ",
+	"下": "した",
+	"中": "なか",
+	"外": "そと",
+	"前": "まえ",
+	"後": "うしろ",
+	"右": "みぎ",
+	"左": "ひだり",
+	"大": "おお",
+	"小": "こ",
+	"何": "なに",
+	"一": "いち",
+	"二": "に",
+	"三": "さん",
+	"四": "よん",
+	"五": "ご",
+	"六": "ろく",
+	"七": "なな",
+	"八": "はち",
+	"九": "きゅう",
+	"十": "じゅう",
+	"百": "ひゃく",
+	"千": "せん",
+	"万": "まん",
+	"円": "えん",
+	"年": "ねん",
+	"匹": "ひき",
+	"世": "よ",
+	"焼": "や",
+	"負": "ま",
+	"貸": "か",
+	"取": "と",
+	"見": "み",
+	"来": "く",
+	"行": "い",
+	"書": "か",
+	"聞": "き",
+	"読": "よ",
+	"食": "た",
+	"飲": "の",
+	"言": "い",
+	"話": "はな",
+	"歌": "うた",
+	"笑": "わら",
+	"泣": "な",
+	"鳴": "な",
+	"寝": "ね",
+	"起": "お",
+	"立": "た",
+	"座": "すわ",
+	"走": "はし",
+	"歩": "ある",
+	"住": "す",
+	"売": "う",
+	"買": "か",
+	"待": "ま",
+	"持": "も",
+	"使": "つか",
+	"作": "つく",
+	"切": "き",
+	"着": "き",
+	"煮": "に",
+	"似": "に",
+	"軽": "かる",
+	"重": "おも",
+	"白": "しろ",
+	"赤": "あか",
+	"青": "あお",
+	"黒": "くろ",
+	"新": "あたら",
+	"古": "ふる",
+	"長": "なが",
+	"短": "みじか",
+	"高": "たか",
+	"安": "やす",
+	"確": "たし",
+	"仔": "こ",
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestReadingConverterToReturnHiragana(t *testing.T) {
+	Expect(t, DefaultReadingConverter.Convert("竹藪焼けた"), "たけやぶやけた")
+	Expect(t, DefaultReadingConverter.Convert("私負けましたわ"), "わたしまけましたわ")
+}
+
+func TestReadingConverterToPreferLongestMatch(t *testing.T) {
+	converter := NewReadingConverter(map[string]string{
+		"何":  "なに",
+		"何匹": "なんびき",
+	})
+
+	Expect(t, converter.Convert("何匹"), "なんびき")
+	Expect(t, converter.Convert("何する"), "なにする")
+}
+
+func TestReadingConverterToKeepUnknownCharacters(t *testing.T) {
+	converter := NewReadingConverter(map[string]string{"竹": "たけ"})
+
+	Expect(t, converter.Convert("竹と笹 abc"), "たけと笹 abc")
+}
+
+func TestIsJapaneseToRequireKana(t *testing.T) {
+	Expect(t, isJapanese("竹藪焼けた"), true)
+	Expect(t, isJapanese("タケヤブ"), true)
+	Expect(t, isJapanese("上海自来水来自海上"), false)
+	Expect(t, isJapanese("racecar"), false)
+}
+
+func TestValidateToReturnReadingOfJapanesePhrases(t *testing.T) {
+	palindrome := Palindrome{Phrase: "竹藪焼けた"}
+	palindrome.Validate()
+
+	Expect(t, palindrome.Valid, true)
+	Expect(t, palindrome.Reading, "たけやぶやけた")
+
+	palindrome = Palindrome{Phrase: "racecar"}
+	palindrome.Validate()
+
+	Expect(t, palindrome.Reading, "")
+}
@@ -0,0 +1,329 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+= Validation result
+Engine.Validate reports everything the engine found out about a phrase
+in a ValidationResult, without changing anything else. That's what
+Palindrome.Validate stores along with the phrase, so the verdict can be
+told apart from the rules it was reached with: the options of the
+engine are recorded in the result, so it can be reached again.
+
+The phrase is normalized once, and everything else is found from the
+normalized text. The verdict of ASCII phrases comes from the fast path
+of IsPalindrome (see symmetry.go), so palindromes are only normalized
+by a single pass over their bytes. Other phrases compared by characters
+are normalized in chunks of a few kilobytes by a segmentCache, and long
+ones by the workers of the engine when it has some (see parallel.go).
+Other units are normalized segment by segment (see offsets.go). Only
+when the phrase isn't a palindrome are the offsets of its characters
+tracked, to point at the first MaxMismatches mismatches (see
+diagnostics.go).
+
+The normalized and reversed forms are made of the units the phrase was
+compared by, separated as the unit requires. Runes and graphemes are
+counted in the normalized text, leaving the separators out. The centre
+is the index of the first unit of the second half, which is the middle
+unit when there's an odd number of them.
+
+NormalizationVersion changes whenever a stage reads phrases differently
+than before, so stored results from older rules can be found.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+// Version of the normalization rules the results are reached with
+const NormalizationVersion = 1
+
+// Options of the Engine a result was reached with
+type ValidationOptions struct {
+	Japanese          JapaneseMode `json:"japanese,omitempty" bson:"japanese,omitempty"`
+	Korean            KoreanMode   `json:"korean,omitempty" bson:"korean,omitempty"`
+	Chinese           ChineseMode  `json:"chinese,omitempty" bson:"chinese,omitempty"`
+	Profile           Profile      `json:"profile,omitempty" bson:"profile,omitempty"`
+	Language          string       `json:"language,omitempty" bson:"language,omitempty"`
+	KeepCompatibility bool         `json:"keep_compatibility,omitempty" bson:"keep_compatibility,omitempty"`
+	Skeleton          bool         `json:"skeleton,omitempty" bson:"skeleton,omitempty"`
+	Confusables       bool         `json:"confusables,omitempty" bson:"confusables,omitempty"`
+	// Set when the phrase was normalized by custom stages instead of
+	// the ones of the options, which can't be recorded
+	Custom bool `json:"custom,omitempty" bson:"custom,omitempty"`
+}
+
+type ValidationResult struct {
+	Valid      bool   `json:"valid" bson:"valid"`
+	Normalized string `json:"normalized" bson:"normalized"`
+	// Not stored, as it's the normalized form read backwards
+	Reversed string `json:"reversed,omitempty" bson:"-"`
+	Unit     Unit   `json:"unit" bson:"unit"`
+	// Options the phrase was normalized with, along with the unit
+	Options ValidationOptions `json:"options" bson:"options"`
+	Version int               `json:"version" bson:"version"`
+	Runes   int               `json:"runes" bson:"runes"`
+	// Grapheme clusters, whatever the unit is. See graphemes.go
+	Graphemes int `json:"graphemes" bson:"graphemes"`
+	// Index of the unit the phrase is mirrored around
+	Centre int `json:"centre" bson:"centre"`
+
+	// Copied to the attributes of the same name of the Palindrome
+	Reading    string     `json:"-" bson:"-"`
+	Folds      []Fold     `json:"-" bson:"-"`
+	Mismatches []Mismatch `json:"-" bson:"-"`
+	// Set when there were more than MaxMismatches
+	MismatchesTruncated bool `json:"-" bson:"-"`
+}
+
+/*
+Validates the phrase, reporting the outcome in the result.
+*/
+func (e *Engine) Validate(phrase string) (ValidationResult, error) {
+	result := ValidationResult{
+		Unit:    e.Unit,
+		Options: e.options(),
+		Version: NormalizationVersion,
+	}
+	if len(phrase) == 0 {
+		return result, errors.New("Invalid length")
+	}
+	if result.Unit == "" {
+		result.Unit = UnitCharacter
+	}
+
+	characters := result.Unit == UnitCharacter && e.Chinese != ChinesePinyin
+	if !characters {
+		return result, e.validateUnits(phrase, &result)
+	}
+
+	var normalized string
+	var chunks []span
+	var err error
+	valid, ascii := false, false
+	if e.readsASCIIAsIs() {
+		valid, ascii = isASCIIPalindrome(phrase)
+	}
+	switch {
+	case ascii && valid:
+		// Without mismatches to locate, a single pass is enough
+		result.Valid = true
+		normalized, _ = normalizeASCII(phrase)
+	case ascii:
+		normalized, chunks, err = e.normalizeValidated(phrase, &result)
+	case e.validatesInParallel(phrase):
+		normalized, chunks, err = e.validateParallel(phrase, &result)
+	default:
+		normalized, chunks, err = e.normalizeValidated(phrase, &result)
+		result.Valid = isSymmetric(normalized)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	result.Normalized = normalized
+	result.Reversed = reverseString(normalized)
+	result.Runes = utf8.RuneCountInString(normalized)
+	result.Graphemes = graphemeCount(normalized)
+	result.Centre = result.Runes / 2
+
+	// The offsets of the units are only needed to point at mismatches
+	if !result.Valid {
+		result.Mismatches, result.MismatchesTruncated, err = e.firstMismatches(phrase, normalized, chunks)
+	}
+
+	return result, err
+}
+
+/*
+Normalizes the whole phrase, keeping the reading and the folds applied
+in the result. Phrases other than Japanese ones are normalized in
+chunks (see offsets.go), which are returned along.
+*/
+func (e *Engine) normalizeValidated(phrase string, result *ValidationResult) (string, []span, error) {
+	normalizer, err := e.foldingNormalizer(&result.Folds)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if isJapanese(phrase) {
+		engine := *e
+		engine.Normalizer = normalizer
+		normalized, reading, err := engine.Normalize(phrase)
+		result.Reading = reading
+		return normalized, nil, err
+	}
+
+	chunks := e.normalizeChunks(phrase, 0, len(phrase), e.segmentCache(normalizer))
+	return joinChunks(chunks), chunks, nil
+}
+
+/*
+Lists the first MaxMismatches pairs of characters of the phrase that
+don't match, and reports whether there were more. Without the chunks
+the phrase was normalized in, the whole phrase is normalized again.
+*/
+func (e *Engine) firstMismatches(phrase, normalized string, chunks []span) ([]Mismatch, bool, error) {
+	if chunks != nil {
+		return e.chunkMismatches(phrase, normalized, chunks, MaxMismatches)
+	}
+
+	units, err := e.units(phrase)
+	if err != nil {
+		return nil, false, err
+	}
+
+	mismatches, truncated := mismatchesIn(phrase, units, MaxMismatches)
+	return mismatches, truncated, nil
+}
+
+/*
+Reports whether the phrase is normalized and compared by the workers
+of the engine, which ASCII phrases read by the fast path are not.
+*/
+func (e *Engine) validatesInParallel(phrase string) bool {
+	if !e.comparesInParallel(phrase) || e.Normalizer != nil || isJapanese(phrase) {
+		return false
+	}
+
+	return !e.readsASCIIAsIs() || !isASCII(phrase)
+}
+
+/*
+Normalizes the whole phrase and compares it with the workers of the
+engine (see parallel.go), keeping the verdict and the folds applied in
+the result. The chunks the phrase was normalized in are returned along.
+*/
+func (e *Engine) validateParallel(phrase string, result *ValidationResult) (string, []span, error) {
+	ctx := context.Background()
+	chunks, err := e.normalizeParallel(ctx, phrase, &result.Folds)
+	if err != nil {
+		return "", nil, err
+	}
+
+	normalized := joinChunks(chunks)
+	result.Valid, err = isSymmetricParallel(ctx, []rune(normalized), e.Workers)
+	return normalized, chunks, err
+}
+
+/*
+Validates the phrase by units other than characters, normalizing it
+segment by segment.
+*/
+func (e *Engine) validateUnits(phrase string, result *ValidationResult) error {
+	normalizer, err := e.foldingNormalizer(&result.Folds)
+	if err != nil {
+		return err
+	}
+
+	engine := *e
+	engine.Normalizer = normalizer
+	units, err := engine.units(phrase)
+	if err != nil {
+		return err
+	}
+
+	if isJapanese(phrase) {
+		result.Reading = e.converter().Convert(phrase)
+	}
+
+	// Words and lines are separated, so no cluster spans two of them
+	separated := result.Unit == UnitWord || result.Unit == UnitLine
+	var text strings.Builder
+	reversed := make([]span, len(units))
+	for i, unit := range units {
+		reversed[len(units)-1-i] = unit
+		result.Runes += utf8.RuneCountInString(unit.text)
+		if separated {
+			result.Graphemes += graphemeCount(unit.text)
+		} else {
+			text.WriteString(unit.text)
+		}
+	}
+	if !separated {
+		result.Graphemes = graphemeCount(text.String())
+	}
+
+	result.Valid = isSymmetricUnits(units)
+	result.Normalized = result.Unit.join(units)
+	result.Reversed = result.Unit.join(reversed)
+	result.Centre = len(units) / 2
+	if !result.Valid {
+		result.Mismatches, result.MismatchesTruncated = mismatchesIn(phrase, units, MaxMismatches)
+	}
+
+	return nil
+}
+
+func (e *Engine) options() ValidationOptions {
+	return ValidationOptions{
+		Japanese:          e.Japanese,
+		Korean:            e.Korean,
+		Chinese:           e.Chinese,
+		Profile:           e.Profile,
+		Language:          e.Language,
+		KeepCompatibility: e.KeepCompatibility,
+		Skeleton:          e.Skeleton,
+		Confusables:       e.Confusables,
+		Custom:            e.Normalizer != nil,
+	}
+}
+
+/*
+Returns s with its runes in reverse order.
+*/
+func reverseString(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := len(s); i > 0; {
+		r, size := utf8.DecodeLastRuneInString(s[:i])
+		b.WriteRune(r)
+		i -= size
+	}
+
+	return b.String()
+}
+
+/*
+Returns the number of grapheme clusters of s.
+*/
+func graphemeCount(s string) int {
+	if isASCII(s) {
+		// CR LF is the only cluster of more than one ASCII rune
+		return len(s) - strings.Count(s, "\r\n")
+	}
+
+	runes := []rune(s)
+
+	count := 0
+	for i := 0; i < len(runes); i += graphemeLength(runes[i:]) {
+		count++
+	}
+
+	return count
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestEngineValidateToReturnResult(t *testing.T) {
+	result, err := new(Engine).Validate("Racecar!")
+
+	Expect(t, err, nil)
+	Expect(t, result.Valid, true)
+	Expect(t, result.Normalized, "racecar")
+	Expect(t, result.Reversed, "racecar")
+	Expect(t, result.Unit, UnitCharacter)
+	Expect(t, result.Version, NormalizationVersion)
+	Expect(t, result.Runes, 7)
+	Expect(t, result.Graphemes, 7)
+	Expect(t, result.Centre, 3)
+}
+
+func TestEngineValidateToReverseByUnit(t *testing.T) {
+	engine := &Engine{Unit: UnitWord}
+
+	result, err := engine.Validate("Fall leaves after leaves fall")
+	Expect(t, err, nil)
+	Expect(t, result.Valid, true)
+	Expect(t, result.Normalized, "fall leaves after leaves fall")
+	Expect(t, result.Runes, 25)
+	Expect(t, result.Centre, 2)
+
+	result, _ = engine.Validate("Just an usual phrase")
+	Expect(t, result.Valid, false)
+	Expect(t, result.Reversed, "phrase usual an just")
+	Expect(t, result.Centre, 2)
+	Expect(t, len(result.Mismatches), 2)
+}
+
+func TestEngineValidateToCountGraphemes(t *testing.T) {
+	engine := &Engine{Unit: UnitGrapheme}
+
+	result, err := engine.Validate("évé")
+	Expect(t, err, nil)
+	Expect(t, result.Valid, true)
+	Expect(t, result.Runes, 3)
+	Expect(t, result.Graphemes, 3)
+
+	engine.KeepCompatibility = true
+	result, _ = engine.Validate("\U0001F44D\U0001F3FD pup \U0001F44D\U0001F3FD")
+	Expect(t, result.Valid, true)
+	Expect(t, result.Runes, 7)
+	Expect(t, result.Graphemes, 5)
+	Expect(t, result.Reversed, result.Normalized)
+}
+
+func TestEngineValidateToCountGraphemesOfWholePhrase(t *testing.T) {
+	result, err := new(Engine).Validate("\U0001F44D\U0001F3FDa\U0001F44D\U0001F3FD")
+	Expect(t, err, nil)
+	Expect(t, result.Unit, UnitCharacter)
+	Expect(t, result.Runes, 5)
+	Expect(t, result.Graphemes, 3)
+
+	// Each word is counted on its own
+	result, _ = (&Engine{Unit: UnitWord}).Validate("काका काका")
+	Expect(t, result.Runes, 8)
+	Expect(t, result.Graphemes, 4)
+}
+
+func TestEngineValidateToRecordOptions(t *testing.T) {
+	engine := &Engine{
+		Japanese:          JapaneseStrict,
+		Korean:            KoreanJamo,
+		Chinese:           ChineseStrict,
+		Profile:           ProfileRTL,
+		Language:          "tr",
+		KeepCompatibility: true,
+		Skeleton:          true,
+		Confusables:       true,
+	}
+
+	result, err := engine.Validate("Ilık kılı")
+	Expect(t, err, nil)
+	Expect(t, result.Options, ValidationOptions{
+		Japanese:          JapaneseStrict,
+		Korean:            KoreanJamo,
+		Chinese:           ChineseStrict,
+		Profile:           ProfileRTL,
+		Language:          "tr",
+		KeepCompatibility: true,
+		Skeleton:          true,
+		Confusables:       true,
+	})
+
+	engine = &Engine{Normalizer: CaseFolding}
+	result, _ = engine.Validate("Racecar")
+	Expect(t, result.Options.Custom, true)
+}
+
+func TestEngineValidateToMatchIsPalindrome(t *testing.T) {
+	tests := []struct {
+		phrase string
+		engine *Engine
+	}{
+		{"Was it a cat I saw?", new(Engine)},
+		{"Was it a dog I saw?", new(Engine)},
+		{"Łapa pał", new(Engine)},
+		{"竹藪焼けた", new(Engine)},
+		{"İki ki", &Engine{Language: "tr"}},
+		{"Fall leaves after leaves fall", &Engine{Unit: UnitWord}},
+		{"事实是", &Engine{Chinese: ChinesePinyin}},
+		{"விகடகவி", &Engine{Unit: UnitAkshara}},
+	}
+
+	for _, test := range tests {
+		result, err := test.engine.Validate(test.phrase)
+		Expect(t, err, nil)
+
+		valid, reading, _ := test.engine.IsPalindrome(test.phrase)
+		Expect(t, result.Valid, valid)
+		Expect(t, result.Reading, reading)
+
+		folds, _ := test.engine.Folds(test.phrase)
+		Expect(t, len(result.Folds), len(folds))
+	}
+}
+
+func TestEngineValidateToFailOnEmptyPhrase(t *testing.T) {
+	_, err := new(Engine).Validate("")
+
+	ExpectNotNil(t, err)
+}
+
+func TestValidateToKeepResult(t *testing.T) {
+	palindrome := Palindrome{Phrase: "Rats live on no evil star", Profile: ProfileRTL}
+	palindrome.Validate()
+
+	ExpectNotNil(t, palindrome.Result)
+	Expect(t, palindrome.Valid, palindrome.Result.Valid)
+	Expect(t, palindrome.Result.Options.Profile, ProfileRTL)
+	Expect(t, palindrome.Result.Normalized, "ratsliveonnoevilstar")
+}
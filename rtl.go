@@ -0,0 +1,144 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+= Right-to-left profile
+Arabic harakat and Hebrew niqqud are nonspacing marks, so MarkRemoval
+already drops them. Some other forms of the same letters are not marks,
+and the rtl profile folds them:
+
+	tatweel            the Arabic elongation ـ is dropped
+	presentation forms the contextual forms of Arabic letters (ﻣ, ﻤ)
+	                   are replaced by the letters, even when the
+	                   compatibility folding is off
+	final letters      the Hebrew final forms ך ם ן ף ץ are read as
+	                   כ מ נ פ צ
+	punctuation        Arabic and Hebrew punctuation (، ؟ ־ ׃) is
+	                   dropped, like ASCII punctuation
+
+Reversing a phrase moves its last letter to the beginning, so a final
+form would never match the regular form at the other end otherwise:
+
+	ילד כותב בתוך דלי
+*/
+
+package main
+
+import (
+	"unicode"
+
+	// Third party packages
+	"golang.org/x/text/unicode/norm"
+)
+
+type Profile string
+
+const (
+	ProfileRTL Profile = "rtl"
+)
+
+const tatweel = 'ـ'
+
+var hebrewFinalLetters = map[rune]string{
+	'ך': "כ",
+	'ם': "מ",
+	'ן': "נ",
+	'ף': "פ",
+	'ץ': "צ",
+}
+
+var rtlPunctuation = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x05BE, 0x05C0, 2},
+		{0x05C3, 0x05C6, 3},
+		{0x05F3, 0x05F4, 1},
+		{0x060C, 0x061B, 0x061B - 0x060C},
+		{0x061F, 0x066A, 0x066A - 0x061F},
+		{0x066B, 0x066D, 1},
+		{0x06D4, 0x06D4, 1},
+	},
+}
+
+var (
+	PresentationFormFolding Normalizer = NormalizerFunc(foldPresentationForms)
+
+	TatweelRemoval Normalizer = RemoveFunc(func(r rune) bool {
+		return r == tatweel
+	})
+
+	FinalLetterFolding = Transliteration(hebrewFinalLetters)
+
+	RTLPunctuationStripping Normalizer = RemoveFunc(func(r rune) bool {
+		return unicode.Is(rtlPunctuation, r)
+	})
+)
+
+/*
+Returns the stages implementing the profile. The empty profile needs
+none.
+*/
+func (profile Profile) normalizer() (Pipeline, bool) {
+	switch profile {
+	case "":
+		return nil, true
+	case ProfileRTL:
+		return Pipeline{
+			PresentationFormFolding,
+			TatweelRemoval,
+			FinalLetterFolding,
+			RTLPunctuationStripping,
+		}, true
+	}
+
+	return nil, false
+}
+
+/*
+Replaces the Arabic and Hebrew presentation forms of s by their
+compatibility decomposition, leaving every other rune as it is.
+*/
+func foldPresentationForms(s string) string {
+	var buf []byte
+	for i, r := range s {
+		if !isPresentationForm(r) {
+			if buf != nil {
+				buf = append(buf, string(r)...)
+			}
+			continue
+		}
+
+		if buf == nil {
+			buf = append(buf, s[:i]...)
+		}
+		buf = append(buf, norm.NFKC.String(string(r))...)
+	}
+
+	if buf == nil {
+		return s
+	}
+	return string(buf)
+}
+
+func isPresentationForm(r rune) bool {
+	switch {
+	case r >= 0xFB1D && r <= 0xFB4F: // Hebrew
+		return true
+	case r >= 0xFB50 && r <= 0xFDFF: // Arabic A
+		return true
+	case r >= 0xFE70 && r <= 0xFEFF: // Arabic B
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestPresentationFormFoldingToReturnLetters(t *testing.T) {
+	Expect(t, PresentationFormFolding.Normalize("\uFEDF\uFEDC\uFEDE ok"), "\u0644\u0643\u0644 ok")
+	Expect(t, PresentationFormFolding.Normalize("\uFB2F"), "\u05D0\u05B8")
+	Expect(t, PresentationFormFolding.Normalize("Ｒ"), "Ｒ")
+}
+
+func TestFinalLetterFoldingToReturnRegularForms(t *testing.T) {
+	Expect(t, FinalLetterFolding.Normalize("ךםןףץ"), "כמנפצ")
+}
+
+func TestTatweelRemovalToDropElongation(t *testing.T) {
+	Expect(t, TatweelRemoval.Normalize("مـودته"), "مودته")
+}
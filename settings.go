@@ -16,16 +16,23 @@ limitations under the License.
 
 package main
 
+import (
+	"runtime"
+)
+
 type Settings struct {
 	// Host name / address
 	HostName string
 	// database name
 	DbName string
+	// goroutines validating each long phrase, see parallel.go
+	Workers int
 }
 
 func GetSettings() Settings {
 	return Settings {
 		HostName: "localhost",
 		DbName: "gopal",
+		Workers: runtime.NumCPU(),
 	}
 }
\ No newline at end of file
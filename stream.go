@@ -0,0 +1,272 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+= Streaming validation
+Generated corpora can be hundreds of megabytes long, too much to be
+kept in memory a few times over as Validate does. ValidateStream reads
+the text from an io.Reader instead, normalizing it chunk by chunk as it
+arrives.
+
+Chunks are only normalized up to the last segment known to be
+complete (see offsets.go); the rest waits for the next chunk. Japanese
+text is normalized segment by segment, as the dictionary words must
+be found first, while any other text is normalized in a single call. The
+normalized runes are kept in memory up to StreamMemoryLimit. Past that,
+they are spilled to a temporary file with a fixed size of 4 bytes per
+rune, so the file can be read backward as easily as forward. The
+symmetry is then checked reading the runes from both ends at once, a
+block at a time.
+
+The text is read as Japanese from the first chunk with kana in it,
+which is the same as Validate does for any text shorter than a chunk.
+Only the character unit is supported, and not in the pinyin mode.
+*/
+
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+
+	// Third party packages
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// Normalized runes kept in memory before spilling to disk
+	StreamMemoryLimit = 1 << 20
+
+	streamChunkSize = 64 << 10
+	// Bytes left unprocessed until more text arrives, so segments
+	// and dictionary words are never split between chunks
+	streamLookahead = 1 << 10
+	// Runes read at once from each end of a spill file
+	streamBlockSize = 16 << 10
+)
+
+type StreamResult struct {
+	Valid bool `json:"valid"`
+	// Bytes read from the stream
+	Bytes int64 `json:"bytes"`
+	// Runes left after normalization
+	Runes int64 `json:"runes"`
+}
+
+/*
+Validates the text read from r until EOF.
+*/
+func (e *Engine) ValidateStream(r io.Reader) (StreamResult, error) {
+	var result StreamResult
+	if e.Unit != "" && e.Unit != UnitCharacter {
+		return result, errors.New("Unsupported unit")
+	}
+	if e.Chinese == ChinesePinyin {
+		return result, errors.New("Unsupported Chinese mode")
+	}
+
+	normalizer, err := e.normalizer()
+	if err != nil {
+		return result, err
+	}
+
+	store := &runeStore{limit: StreamMemoryLimit}
+	defer store.Close()
+
+	var converter *ReadingConverter
+	buf := make([]byte, 0, streamChunkSize+streamLookahead)
+	chunk := make([]byte, streamChunkSize)
+	for atEOF := false; !atEOF; {
+		n, err := io.ReadFull(r, chunk)
+		switch err {
+		case nil:
+		case io.EOF, io.ErrUnexpectedEOF:
+			atEOF = true
+		default:
+			return result, err
+		}
+		result.Bytes += int64(n)
+		buf = append(buf, chunk[:n]...)
+
+		text := string(buf)
+		if converter == nil && isJapanese(text) {
+			converter = e.converter()
+		}
+
+		// Without dictionary words to look for, everything up to the
+		// last segment boundary is normalized at once
+		i := 0
+		if converter == nil {
+			i = lastBoundary(buf, atEOF)
+			if err := store.WriteString(normalizer.Normalize(text[:i])); err != nil {
+				return result, err
+			}
+		}
+		for i < len(text) && (atEOF || len(text)-i >= streamLookahead) {
+			segment, size := nextSegment(text[i:], converter, atEOF)
+			if size < 0 {
+				break
+			}
+			if err := store.WriteString(normalizer.Normalize(segment)); err != nil {
+				return result, err
+			}
+			i += size
+		}
+		buf = append(buf[:0], buf[i:]...)
+	}
+
+	result.Runes = store.Len()
+	result.Valid, err = store.IsSymmetric()
+	return result, err
+}
+
+/*
+Returns the position of the last segment boundary of buf that is
+followed by at least streamLookahead bytes.
+*/
+func lastBoundary(buf []byte, atEOF bool) int {
+	if atEOF {
+		return len(buf)
+	}
+
+	n := len(buf) - streamLookahead
+	if n <= 0 {
+		return 0
+	}
+	if boundary := norm.NFKC.LastBoundary(buf[:n]); boundary > 0 {
+		return boundary
+	}
+
+	return 0
+}
+
+/*
+Sequence of runes kept in memory up to limit, and in a temporary file
+past that.
+*/
+type runeStore struct {
+	limit  int
+	runes  []rune
+	file   *os.File
+	writer *bufio.Writer
+	length int64
+}
+
+func (s *runeStore) WriteString(text string) error {
+	for _, r := range text {
+		if s.file == nil && len(s.runes) >= s.limit {
+			if err := s.spill(); err != nil {
+				return err
+			}
+		}
+
+		s.length++
+		if s.file == nil {
+			s.runes = append(s.runes, r)
+			continue
+		}
+
+		var b [4]byte
+		binary.LittleEndian.PutUint32(b[:], uint32(r))
+		if _, err := s.writer.Write(b[:]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *runeStore) Len() int64 {
+	return s.length
+}
+
+/*
+Moves the runes kept in memory to a temporary file.
+*/
+func (s *runeStore) spill() error {
+	file, err := ioutil.TempFile("", "gopal-stream-")
+	if err != nil {
+		return err
+	}
+
+	s.file = file
+	s.writer = bufio.NewWriter(file)
+	runes := s.runes
+	s.runes = nil
+	s.length -= int64(len(runes))
+
+	return s.WriteString(string(runes))
+}
+
+/*
+Compares runes 1st to last position up to middle position.
+*/
+func (s *runeStore) IsSymmetric() (bool, error) {
+	if s.file == nil {
+		for i, j := 0, len(s.runes)-1; i < j; i, j = i+1, j-1 {
+			if s.runes[i] != s.runes[j] {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+
+	if err := s.writer.Flush(); err != nil {
+		return false, err
+	}
+
+	front := make([]byte, 4*streamBlockSize)
+	back := make([]byte, 4*streamBlockSize)
+	for lo, hi := int64(0), s.length; hi-lo > 1; {
+		// Runes to compare from each end in this block
+		n := (hi - lo) / 2
+		if n > streamBlockSize {
+			n = streamBlockSize
+		}
+
+		if _, err := s.file.ReadAt(front[:4*n], 4*lo); err != nil {
+			return false, err
+		}
+		if _, err := s.file.ReadAt(back[:4*n], 4*(hi-n)); err != nil {
+			return false, err
+		}
+
+		for k := int64(0); k < n; k++ {
+			first := front[4*k : 4*k+4]
+			last := back[4*(n-1-k) : 4*(n-k)]
+			if binary.LittleEndian.Uint32(first) != binary.LittleEndian.Uint32(last) {
+				return false, nil
+			}
+		}
+		lo, hi = lo+n, hi-n
+	}
+
+	return true, nil
+}
+
+/*
+Removes the temporary file, if any.
+*/
+func (s *runeStore) Close() error {
+	if s.file == nil {
+		return nil
+	}
+
+	s.file.Close()
+	return os.Remove(s.file.Name())
+}
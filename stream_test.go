@@ -0,0 +1,71 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestValidateStreamToAssertPalindromes(t *testing.T) {
+	engine := new(Engine)
+
+	result, err := engine.ValidateStream(strings.NewReader("Go hang a salami, I'm a lasagna hog"))
+	Expect(t, err, nil)
+	Expect(t, result.Valid, true)
+	Expect(t, result.Bytes, int64(35))
+	Expect(t, result.Runes, int64(26))
+
+	result, _ = engine.ValidateStream(strings.NewReader("竹藪焼けた"))
+	Expect(t, result.Valid, true)
+
+	result, _ = engine.ValidateStream(strings.NewReader("Just an usual phrase"))
+	Expect(t, result.Valid, false)
+}
+
+func TestValidateStreamToHandleSegmentsAcrossChunks(t *testing.T) {
+	// Accents decomposed into a combining mark are split from their
+	// base letter at some chunk boundary
+	phrase := strings.Repeat("A\u0301b", 20000) + "c" + strings.Repeat("bA\u0301", 20000)
+
+	result, err := new(Engine).ValidateStream(iotest.HalfReader(strings.NewReader(phrase)))
+	Expect(t, err, nil)
+	Expect(t, result.Valid, true)
+}
+
+func TestValidateStreamToFailOnUnsupportedUnit(t *testing.T) {
+	engine := &Engine{Unit: UnitWord}
+
+	_, err := engine.ValidateStream(strings.NewReader("racecar"))
+	ExpectNotNil(t, err)
+}
+
+func TestValidateStreamToFailOnReadError(t *testing.T) {
+	_, err := new(Engine).ValidateStream(iotest.ErrReader(io.ErrClosedPipe))
+
+	Expect(t, err, io.ErrClosedPipe)
+}
+
+func TestRuneStoreToSpillToDisk(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected bool
+	}{
+		{"abcdefgfedcba", true},
+		{"abcdeffedcba", true},
+		{"abcdefgfedcbb", false},
+		{"xbcdefgfedcba", false},
+	}
+
+	for _, test := range tests {
+		store := &runeStore{limit: 4}
+		store.WriteString(test.text)
+
+		ExpectNotNil(t, store.file)
+		valid, err := store.IsSymmetric()
+		Expect(t, err, nil)
+		Expect(t, valid, test.expected)
+		Expect(t, store.Len(), int64(len(test.text)))
+		Expect(t, store.Close(), nil)
+	}
+}
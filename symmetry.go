@@ -0,0 +1,291 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+= Symmetry check
+Normalizing the whole phrase before comparing it makes a few copies of
+it, one per stage, even when the very first and last characters are
+already different. IsPalindrome avoids that by normalizing and
+comparing the phrase from both ends at once.
+
+ASCII phrases need nothing more than lower casing and dropping
+punctuation and white space, which is done while comparing, byte by
+byte, without any allocation. The first non-ASCII byte found ends that
+fast path, unless the answer is already known.
+
+Any other phrase is normalized a chunk at a time from each end. Chunks
+end at segment boundaries (see offsets.go), so they normalize the same
+as the whole phrase does, and the comparison stops at the first pair of
+runes that don't match. They start small and grow, so mismatches near
+the ends are found early while long palindromes are still normalized in
+few calls. Japanese phrases, units other than characters and custom
+normalizers still normalize the whole phrase first.
+
+Running every stage over each chunk takes a pass over it per stage,
+though, while the segments of a long phrase are the same few letters
+over and over. Unless the options read the phrase differently by its
+context, such as the case mapping of a language does, the chunks are
+normalized by a segmentCache instead, which normalizes each segment
+only the first time it's found.
+*/
+
+package main
+
+import (
+	"unicode/utf8"
+
+	// Third party packages
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// Bytes of the phrase normalized at once from each end, at first
+	symmetryChunkSize = 1 << 10
+	// Chunks double in size up to that, as the longer the phrase
+	// is symmetric, the more likely it is to be a palindrome
+	symmetryMaxChunkSize = 64 << 10
+)
+
+// Segments a segmentCache remembers the normalized form of, at most
+const segmentCacheSize = 4 << 10
+
+// ASCII bytes dropped by PunctuationStripping
+var asciiStripped [utf8.RuneSelf]bool
+
+func init() {
+	for c := range asciiStripped {
+		asciiStripped[c] = isASCIIPunctOrSpace(rune(c))
+	}
+}
+
+/*
+Reports whether the options leave ASCII text as the default pipeline
+does. Unknown options are left to the normalizer to report.
+*/
+func (e *Engine) readsASCIIAsIs() bool {
+	// The case mapping of some languages differs on ASCII (I => ı)
+	if e.Normalizer != nil || e.Language != "" {
+		return false
+	}
+
+	switch e.Unit {
+	case "", UnitCharacter:
+	default:
+		return false
+	}
+
+	switch e.Japanese {
+	case "", JapaneseStrict, JapaneseVoicingInsensitive, JapaneseKanaFolded:
+	default:
+		return false
+	}
+
+	switch e.Korean {
+	case "", KoreanSyllable, KoreanJamo, KoreanIgnoreFinals:
+	default:
+		return false
+	}
+
+	switch e.Chinese {
+	case "", ChineseVariantFolded, ChineseStrict:
+	default:
+		return false
+	}
+
+	switch e.Profile {
+	case "", ProfileRTL:
+	default:
+		return false
+	}
+
+	return true
+}
+
+/*
+Reports whether s is a palindrome, reading it from both ends as long as
+only ASCII is found. ok is false when s had to be read further than
+that to know it.
+*/
+func isASCIIPalindrome(s string) (valid bool, ok bool) {
+	i, j := 0, len(s)-1
+	for {
+		for ; i < j; i++ {
+			if s[i] >= utf8.RuneSelf {
+				return false, false
+			}
+			if !asciiStripped[s[i]] {
+				break
+			}
+		}
+		for ; i < j; j-- {
+			if s[j] >= utf8.RuneSelf {
+				return false, false
+			}
+			if !asciiStripped[s[j]] {
+				break
+			}
+		}
+
+		if i >= j {
+			if i == j && s[i] >= utf8.RuneSelf {
+				return false, false
+			}
+			return true, true
+		}
+
+		// A combining mark right after s[i] would be composed with it
+		if s[i+1] >= utf8.RuneSelf {
+			return false, false
+		}
+		if lowerASCII(s[i]) != lowerASCII(s[j]) {
+			return false, true
+		}
+		i, j = i+1, j-1
+	}
+}
+
+/*
+Normalizes s as the default pipeline does, as long as it's made of
+ASCII only. ok is false otherwise.
+*/
+func normalizeASCII(s string) (normalized string, ok bool) {
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c >= utf8.RuneSelf:
+			return "", false
+		case !asciiStripped[c]:
+			b = append(b, lowerASCII(c))
+		}
+	}
+
+	return string(b), true
+}
+
+func lowerASCII(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+/*
+Reports whether the phrase reads the same backward once normalized,
+normalizing it a chunk at a time from each end.
+*/
+func isSymmetricChunks(phrase string, normalizer Normalizer) bool {
+	// Normalized runes not compared yet, from each end
+	var front, back string
+	i, j := 0, len(phrase)
+	for size := symmetryChunkSize; ; {
+		for front == "" && i < j {
+			end := j
+			if j-i > size {
+				if end = segmentStart(phrase, i+size, i); end == i {
+					end = j
+				}
+			}
+			front, i = normalizer.Normalize(phrase[i:end]), end
+		}
+		for back == "" && i < j {
+			start := i
+			if j-i > size {
+				start = segmentStart(phrase, j-size, i)
+			}
+			back, j = normalizer.Normalize(phrase[start:j]), start
+		}
+		if size < symmetryMaxChunkSize {
+			size *= 2
+		}
+
+		// The whole phrase is normalized, what's left is the middle
+		if front == "" || back == "" {
+			return isSymmetric(front + back)
+		}
+
+		for front != "" && back != "" {
+			first, sizeOfFirst := utf8.DecodeRuneInString(front)
+			last, sizeOfLast := utf8.DecodeLastRuneInString(back)
+			if first != last {
+				return false
+			}
+			front, back = front[sizeOfFirst:], back[:len(back)-sizeOfLast]
+		}
+	}
+}
+
+/*
+Normalizes text segment by segment, remembering what each segment was
+normalized into. ASCII characters skip the stages. It's not safe for
+concurrent use.
+*/
+type segmentCache struct {
+	normalizer Normalizer
+	segments   map[string]string
+}
+
+/*
+Returns a segmentCache of the normalizer of the engine, or the
+normalizer itself when the options don't allow it.
+*/
+func (e *Engine) segmentCache(normalizer Normalizer) Normalizer {
+	// The options reading ASCII as it is don't look at the context
+	if !e.readsASCIIAsIs() {
+		return normalizer
+	}
+
+	return &segmentCache{normalizer, make(map[string]string)}
+}
+
+func (c *segmentCache) Normalize(s string) string {
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); {
+		// No mark follows, so the ASCII character is a segment alone
+		if s[i] < utf8.RuneSelf && (i+1 == len(s) || s[i+1] < utf8.RuneSelf) {
+			if !asciiStripped[s[i]] {
+				b = append(b, lowerASCII(s[i]))
+			}
+			i++
+			continue
+		}
+
+		size := segmentSize(s[i:], true)
+		segment := s[i : i+size]
+		normalized, ok := c.segments[segment]
+		if !ok {
+			normalized = c.normalizer.Normalize(segment)
+			if len(c.segments) < segmentCacheSize {
+				c.segments[segment] = normalized
+			}
+		}
+		b = append(b, normalized...)
+		i += size
+	}
+
+	return string(b)
+}
+
+/*
+Returns the start of the segment of s that pos is in, or min when the
+segment starts before it.
+*/
+func segmentStart(s string, pos, min int) int {
+	for ; pos > min; pos-- {
+		if utf8.RuneStart(s[pos]) && norm.NFKC.PropertiesString(s[pos:]).BoundaryBefore() {
+			return pos
+		}
+	}
+
+	return min
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+
+	// Third party packages
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestIsASCIIPalindromeToMatchDefaultNormalizer(t *testing.T) {
+	phrases := []string{
+		"Go hang a salami, I'm a lasagna hog",
+		"Was it a cat I saw?",
+		"Just an usual phrase",
+		"A\tb\nA",
+		"?!",
+		"x",
+		"ab\x00ba",
+	}
+
+	for _, phrase := range phrases {
+		valid, ok := isASCIIPalindrome(phrase)
+		Expect(t, ok, true)
+		Expect(t, valid, isSymmetric(DefaultNormalizer.Normalize(phrase)))
+	}
+}
+
+func TestIsASCIIPalindromeToStopOnOtherCharacters(t *testing.T) {
+	tests := []struct {
+		phrase string
+		valid  bool
+		ok     bool
+	}{
+		// Decided before reaching the accent
+		{"ab é bc", false, true},
+		// The mark composes with the a
+		{"báb", false, false},
+		{"abcé", false, false},
+		{"a é a", false, false},
+	}
+
+	for _, test := range tests {
+		valid, ok := isASCIIPalindrome(test.phrase)
+		Expect(t, valid, test.valid)
+		Expect(t, ok, test.ok)
+	}
+}
+
+func TestIsSymmetricChunksToMatchWholeNormalization(t *testing.T) {
+	half := strings.Repeat("Áb, Ünï ﬁ ", 500)
+	phrases := []string{
+		half + "Ω" + reverse(half),
+		half + reverse(half),
+		half + "Ω" + reverse(half) + "x",
+		"x" + half + reverse(half),
+		"DÁBALE ARROZ A LA ZORRA EL ABAD",
+	}
+
+	engine := new(Engine)
+	normalizer, _ := engine.normalizer()
+	for _, phrase := range phrases {
+		word, _, _ := engine.Normalize(phrase)
+		Expect(t, isSymmetricChunks(phrase, normalizer), isSymmetric(word))
+	}
+}
+
+func TestSegmentCacheToMatchWholeNormalization(t *testing.T) {
+	phrases := []string{
+		"Dábale arroz a la zorra él abad, e\u0301 ⁹ K ﬁ æ ß Ｒａｃｅ",
+		"ﾀｹﾔﾌﾞﾔｹﾀ ガ が ａ\u0301",
+		"만남 다시 합창합시다 ᄀ\u1161",
+		"ילד כותב בתוך דלי, مودته تدوم ـ ﻻ",
+		"上海自來水来自海上 Rаcеcаr Нannah",
+		"दामाद விகடகவி",
+	}
+	engines := []*Engine{
+		new(Engine),
+		{Profile: ProfileRTL},
+		{KeepCompatibility: true, Confusables: true},
+		{Korean: KoreanJamo, Chinese: ChineseStrict, Skeleton: true},
+	}
+
+	for _, engine := range engines {
+		normalizer, _ := engine.normalizer()
+		cache := engine.segmentCache(normalizer)
+		for _, phrase := range phrases {
+			// Twice, the second time from the segments remembered
+			Expect(t, cache.Normalize(phrase), normalizer.Normalize(phrase))
+			Expect(t, cache.Normalize(phrase), normalizer.Normalize(phrase))
+		}
+	}
+}
+
+func TestIsPalindromeToNotAllocateOnASCII(t *testing.T) {
+	engine := &Engine{Profile: ProfileRTL}
+	phrase := "Go hang a salami, I'm a lasagna hog"
+
+	allocs := testing.AllocsPerRun(100, func() {
+		engine.IsPalindrome(phrase)
+	})
+	Expect(t, allocs, float64(0))
+}
+
+func TestIsPalindromeToApplyLanguageOnASCII(t *testing.T) {
+	engine := &Engine{Language: "tr"}
+
+	valid, _, err := engine.IsPalindrome("Ili")
+	Expect(t, err, nil)
+	Expect(t, valid, false)
+
+	engine = &Engine{Japanese: "unknown"}
+	_, _, err = engine.IsPalindrome("racecar")
+	ExpectNotNil(t, err)
+}
+
+// Reverses s rune by rune, keeping the combining marks after the
+// letter they belong to
+func reverse(s string) string {
+	runes := []rune(s)
+	reversed := make([]rune, 0, len(runes))
+	for i := len(runes); i > 0; {
+		j := i - 1
+		for j > 0 && unicode.Is(unicode.Mn, runes[j]) {
+			j--
+		}
+		reversed = append(reversed, runes[j:i]...)
+		i = j
+	}
+	return string(reversed)
+}
+
+/*
+Validation as it was done before the Normalizer pipeline, kept to
+compare the performance with.
+*/
+func legacyIsPalindrome(word string) bool {
+	f := func(r rune) bool {
+		return unicode.Is(unicode.Mn, r) // Mn: nonspacing marks
+	}
+
+	re := regexp.MustCompile("[[:punct:]]|[[:space:]]")
+	word = re.ReplaceAllString(strings.ToLower(word), "")
+
+	t := transform.Chain(norm.NFD, transform.RemoveFunc(f), norm.NFC)
+	word, _, _ = transform.String(t, word)
+
+	for len(word) > 0 {
+		first, sizeOfFirst := utf8.DecodeRuneInString(word)
+		if sizeOfFirst == len(word) {
+			break
+		}
+		last, sizeOfLast := utf8.DecodeLastRuneInString(word)
+		if first != last {
+			return false
+		}
+		word = word[sizeOfFirst : len(word)-sizeOfLast]
+	}
+
+	return true
+}
+
+var benchmarkPhrases = []struct {
+	name   string
+	phrase string
+}{
+	{"racecar", "racecar"},
+	{"salami", "Go hang a salami, I'm a lasagna hog"},
+	{"cat", "Was it a cat I saw?"},
+	{"abad", "DÁBALE ARROZ A LA ZORRA EL ABAD"},
+	{"onibus", "SOCORRAM-ME, SUBI NO ÔNIBUS EM MARROCOS"},
+	{"ascii-1mb", strings.Repeat("Was it a cat I saw? ", 1<<15)},
+	{"accented-1mb", strings.Repeat("Dábale arroz a la zorra él abad ", 1<<15)},
+	{"mismatch-1mb", "x" + strings.Repeat("Was it a cat I saw? ", 1<<15)},
+}
+
+func BenchmarkPalindromeValidator(b *testing.B) {
+	benchmarkValidator(b, func(phrase string) bool {
+		palindrome := Palindrome{Phrase: phrase}
+		palindrome.Validate()
+		return palindrome.Valid
+	})
+}
+
+func benchmarkValidator(b *testing.B, isPalindrome func(phrase string) bool) {
+	for _, benchmark := range benchmarkPhrases {
+		phrase := benchmark.phrase
+		b.Run(benchmark.name, func(b *testing.B) {
+			b.SetBytes(int64(len(phrase)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				isPalindrome(phrase)
+			}
+		})
+	}
+}
+
+func BenchmarkLegacyValidator(b *testing.B) {
+	benchmarkValidator(b, legacyIsPalindrome)
+}
+
+func BenchmarkPipelineValidator(b *testing.B) {
+	engine := new(Engine)
+	benchmarkValidator(b, func(phrase string) bool {
+		word, _, _ := engine.Normalize(phrase)
+		return isSymmetric(word)
+	})
+}
+
+func BenchmarkValidator(b *testing.B) {
+	engine := new(Engine)
+	benchmarkValidator(b, func(phrase string) bool {
+		valid, _, _ := engine.IsPalindrome(phrase)
+		return valid
+	})
+}
@@ -31,7 +31,9 @@ func (h *HandlerTest) SetupTest(f func()) {
 	settings.DbName = "test"
 
 	dao := NewDao(settings)
-    dao.EnsureIndex()
+    if err := dao.EnsureIndex(); err != nil {
+        panic(err)
+    }
 
     defer dao.Close()
 	c := dao.Database().C("palindromes")
@@ -45,6 +47,7 @@ func (h *HandlerTest) SetupTest(f func()) {
 			ID: bson.NewObjectId(),
 			Phrase: fmt.Sprintf("test phrase %d", i),
 		}
+		palindrome.Length = len(palindrome.Phrase)
 		c.Insert(&palindrome)
 		h.Entries[palindrome.ID.Hex()] = palindrome
 	}
@@ -0,0 +1,221 @@
+/*
+Copyright 2017 Masaru Hoshi.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+
+You may obtain a copy of the License at
+	 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+= Comparison units
+Palindromes are usually read character by character, but some of them
+are made of words or even of whole lines that read the same in reverse
+order:
+
+	Fall leaves after leaves fall
+	King, are you glad you are king?
+
+The unit the phrase is compared by is selected in the Engine:
+
+	character  each rune of the normalized phrase (the default)
+	grapheme   each grapheme cluster of the normalized phrase (see
+	           graphemes.go)
+	akshara    each syllable of the Brahmic scripts (see akshara.go)
+	word       each sequence of runes between white spaces
+	line       each line
+
+Words and lines are normalized on their own, with the same rules used
+for characters. The ones left empty after normalization (a lonely
+punctuation mark, a blank line) are ignored.
+*/
+
+package main
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+type Unit string
+
+const (
+	UnitCharacter Unit = "character"
+	UnitGrapheme  Unit = "grapheme"
+	UnitAkshara   Unit = "akshara"
+	UnitWord      Unit = "word"
+	UnitLine      Unit = "line"
+)
+
+/*
+Splits the phrase in the units it's compared by. Each unit keeps the
+bytes of the phrase it came from.
+*/
+func (e *Engine) units(phrase string) ([]span, error) {
+	switch e.Unit {
+	case "", UnitCharacter:
+		spans, err := e.spans(phrase)
+		if err != nil {
+			return nil, err
+		}
+		if e.Chinese == ChinesePinyin {
+			return syllableUnits(phrase, spans), nil
+		}
+		return characterUnits(spans), nil
+	case UnitGrapheme:
+		spans, err := e.spans(phrase)
+		if err != nil {
+			return nil, err
+		}
+		return graphemeUnits(spans), nil
+	case UnitAkshara:
+		spans, err := e.spans(phrase)
+		if err != nil {
+			return nil, err
+		}
+		return aksharaUnits(spans), nil
+	case UnitWord:
+		return e.tokenUnits(phrase, unicode.IsSpace)
+	case UnitLine:
+		return e.tokenUnits(phrase, isLineBreak)
+	}
+
+	return nil, errors.New("Unknown unit")
+}
+
+/*
+Splits normalized spans in one unit per rune.
+*/
+func characterUnits(spans []span) []span {
+	var units []span
+	for _, s := range spans {
+		for _, r := range s.text {
+			units = append(units, span{string(r), s.start, s.end})
+		}
+	}
+
+	return units
+}
+
+/*
+Splits normalized spans in one unit per grapheme cluster.
+*/
+func graphemeUnits(spans []span) []span {
+	return clusterUnits(spans, graphemeLength)
+}
+
+/*
+Splits normalized spans in clusters of runes, as long as length
+returns for the runes each one of them starts with. A cluster may be
+made of runes from several spans, as in a flag or a ZWJ sequence, and
+keeps the bytes of all of them.
+*/
+func clusterUnits(spans []span, length func(runes []rune) int) []span {
+	var runes []rune
+	var owners []span
+	for _, s := range spans {
+		for _, r := range s.text {
+			runes = append(runes, r)
+			owners = append(owners, s)
+		}
+	}
+
+	var units []span
+	for i := 0; i < len(runes); {
+		n := length(runes[i:])
+		units = append(units, span{string(runes[i : i+n]), owners[i].start, owners[i+n-1].end})
+		i += n
+	}
+
+	return units
+}
+
+/*
+Splits the phrase in tokens delimited by the runes for which
+isSeparator returns true, normalizing each one of them.
+*/
+func (e *Engine) tokenUnits(phrase string, isSeparator func(r rune) bool) ([]span, error) {
+	normalizer, err := e.normalizer()
+	if err != nil {
+		return nil, err
+	}
+
+	// Tokens are read as Japanese when the whole phrase is
+	var converter *ReadingConverter
+	if isJapanese(phrase) {
+		converter = e.converter()
+	}
+
+	var units []span
+	start := 0
+	for start < len(phrase) {
+		end := strings.IndexFunc(phrase[start:], isSeparator)
+		if end < 0 {
+			end = len(phrase)
+		} else {
+			end += start
+		}
+
+		text := phrase[start:end]
+		if converter != nil {
+			text = converter.Convert(text)
+		}
+		if text = normalizer.Normalize(text); text != "" {
+			units = append(units, span{text, start, end})
+		}
+
+		// Skip the separator
+		start = end
+		for _, r := range phrase[end:] {
+			if !isSeparator(r) {
+				break
+			}
+			start += len(string(r))
+		}
+	}
+
+	return units, nil
+}
+
+/*
+Compares units 1st to last position up to middle position.
+*/
+func isSymmetricUnits(units []span) bool {
+	for i, j := 0, len(units)-1; i < j; i, j = i+1, j-1 {
+		if units[i].text != units[j].text {
+			return false
+		}
+	}
+
+	return true
+}
+
+/*
+Joins the text of the units back, separated as the unit requires.
+*/
+func (u Unit) join(units []span) string {
+	separator := ""
+	switch u {
+	case UnitWord:
+		separator = " "
+	case UnitLine:
+		separator = "\n"
+	}
+
+	texts := make([]string, len(units))
+	for i, unit := range units {
+		texts[i] = unit.text
+	}
+
+	return strings.Join(texts, separator)
+}
+
+func isLineBreak(r rune) bool {
+	return r == '\n'
+}
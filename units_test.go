@@ -0,0 +1,91 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestValidateAssertsWordPalindromes(t *testing.T) {
+	phrases := []string{
+		"Fall leaves after leaves fall",
+		"King, are you glad you are king?",
+		"You can cage a swallow, can't you, but you can't swallow a cage, can you?",
+	}
+
+	var palindrome Palindrome
+	for _, phrase := range phrases {
+		palindrome = Palindrome{Phrase: phrase, Unit: UnitWord}
+		palindrome.Validate()
+
+		Expect(t, palindrome.Valid, true)
+	}
+}
+
+func TestValidateRefuteWordPalindromesByCharacter(t *testing.T) {
+	palindrome := Palindrome{Phrase: "Fall leaves after leaves fall"}
+	palindrome.Validate()
+
+	Expect(t, palindrome.Valid, false)
+	Expect(t, palindrome.Unit, UnitCharacter)
+}
+
+func TestValidateAssertsLinePalindromes(t *testing.T) {
+	phrase := "Roses are red,\nviolets are blue\n\nroses are red!"
+
+	palindrome := Palindrome{Phrase: phrase, Unit: UnitLine}
+	palindrome.Validate()
+	Expect(t, palindrome.Valid, true)
+
+	palindrome = Palindrome{Phrase: phrase, Unit: UnitWord}
+	palindrome.Validate()
+	Expect(t, palindrome.Valid, false)
+}
+
+func TestValidateToFailOnUnknownUnit(t *testing.T) {
+	palindrome := Palindrome{Phrase: "racecar", Unit: "sentence"}
+
+	ExpectNotNil(t, palindrome.Validate())
+}
+
+func TestWordUnitsToKeepOffsets(t *testing.T) {
+	engine := &Engine{Unit: UnitWord}
+
+	units, _ := engine.units("Go, hang  on!")
+	Expect(t, len(units), 3)
+	Expect(t, units[1], span{"hang", 4, 8})
+	Expect(t, units[2], span{"on", 10, 13})
+}
+
+func TestLongestToFindWordPalindromes(t *testing.T) {
+	engine := &Engine{Unit: UnitWord}
+
+	longest, _ := engine.Longest("So I said: fall leaves after leaves fall.")
+	Expect(t, longest.Normalized, "fall leaves after leaves fall")
+	Expect(t, longest.Text, "fall leaves after leaves fall.")
+}
+
+func TestValidateAssertsGraphemePalindromes(t *testing.T) {
+	phrases := []string{
+		"🇯🇵x🇯🇵",
+		"👍🏽 pup 👍🏽",
+		"👨‍👩‍👧 & 👩‍👩‍👦 & 👨‍👩‍👧",
+	}
+
+	var palindrome Palindrome
+	for _, phrase := range phrases {
+		palindrome = Palindrome{Phrase: phrase, Unit: UnitGrapheme}
+		palindrome.Validate()
+		Expect(t, palindrome.Valid, true)
+
+		palindrome = Palindrome{Phrase: phrase}
+		palindrome.Validate()
+		Expect(t, palindrome.Valid, false)
+	}
+}
+
+func TestGraphemeUnitsToKeepOffsets(t *testing.T) {
+	engine := &Engine{Unit: UnitGrapheme}
+
+	units, _ := engine.units("a 🇯🇵!")
+	Expect(t, len(units), 2)
+	Expect(t, units[1], span{"🇯🇵", 2, 10})
+}